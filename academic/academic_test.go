@@ -0,0 +1,136 @@
+/*
+Copyright 2021 The Go jAccount Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package academic
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/dyweb/go-jaccount/jaccount"
+)
+
+var fall2026 = jaccount.Term{Year: 2026, Semester: jaccount.Fall}
+
+func TestTermCalendar(t *testing.T) {
+	c, ok := Default.Term(fall2026)
+	if !ok {
+		t.Fatal("no calendar for 2026 fall")
+	}
+
+	if got := c.Date(7, time.Monday); got != D(2026, 10, 26) {
+		t.Errorf("Date(7, Monday) = %v", got)
+	}
+	if got := c.Date(1, time.Sunday); got != D(2026, 9, 20) {
+		t.Errorf("Date(1, Sunday) = %v", got)
+	}
+
+	week, weekday, ok := c.Week(D(2026, 10, 28))
+	if !ok || week != 7 || weekday != time.Wednesday {
+		t.Errorf("Week(2026-10-28) = %d, %v, %v", week, weekday, ok)
+	}
+	if _, _, ok := c.Week(D(2027, 1, 4)); ok {
+		t.Error("Week(2027-01-04) is after the last teaching week")
+	}
+
+	tests := []struct {
+		day     Date
+		week    int
+		weekday time.Weekday
+		ok      bool
+	}{
+		{D(2026, 9, 14), 1, time.Monday, true},
+		{D(2026, 10, 2), 0, 0, false},              // holiday
+		{D(2026, 10, 10), 4, time.Wednesday, true}, // make-up day for 10-07
+		{D(2026, 9, 20), 4, time.Tuesday, true},    // make-up day for 10-06
+	}
+	for _, tt := range tests {
+		week, weekday, ok := c.Teaching(tt.day)
+		if week != tt.week || weekday != tt.weekday || ok != tt.ok {
+			t.Errorf("Teaching(%v) = %d, %v, %v, want %d, %v, %v", tt.day, week, weekday, ok, tt.week, tt.weekday, tt.ok)
+		}
+	}
+
+	if got := c.Meetings(3, time.Friday); got != nil {
+		t.Errorf("Meetings(3, Friday) = %v, want none on National Day", got)
+	}
+	if got, want := c.Meetings(4, time.Wednesday), []Date{D(2026, 10, 10)}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Meetings(4, Wednesday) = %v, want %v", got, want)
+	}
+	if got, want := c.Meetings(5, time.Monday), []Date{D(2026, 10, 12)}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Meetings(5, Monday) = %v, want %v", got, want)
+	}
+}
+
+func TestBellSchedule(t *testing.T) {
+	if n, ok := SJTUBells.At(clock(14, 0)); !ok || n != 7 {
+		t.Errorf("At(14:00) = %d, %v, want 7", n, ok)
+	}
+	if _, ok := SJTUBells.At(clock(9, 45)); ok {
+		t.Error("At(9:45) is in a break")
+	}
+	if n, ok := SJTUBells.Next(clock(9, 45)); !ok || n != 3 {
+		t.Errorf("Next(9:45) = %d, %v, want 3", n, ok)
+	}
+}
+
+func TestCalendar_Locate(t *testing.T) {
+	at := time.Date(2026, 10, 28, 6, 10, 0, 0, time.UTC) // 14:10 in Shanghai
+	got, ok := Default.Locate(at)
+	want := Position{Term: fall2026, Week: 7, Weekday: time.Wednesday, Section: 7}
+	if !ok || got != want {
+		t.Errorf("Locate() = %+v, %v, want %+v", got, ok, want)
+	}
+
+	meetings, err := Default.Meetings(fall2026, 7, time.Wednesday, 7, 8)
+	if err != nil || len(meetings) != 1 {
+		t.Fatalf("Meetings() = %v, %v", meetings, err)
+	}
+	if start := meetings[0].Start; !start.Equal(time.Date(2026, 10, 28, 14, 0, 0, 0, Shanghai)) {
+		t.Errorf("Meetings() start = %v", start)
+	}
+	if end := meetings[0].End; !end.Equal(time.Date(2026, 10, 28, 15, 40, 0, 0, Shanghai)) {
+		t.Errorf("Meetings() end = %v", end)
+	}
+}
+
+func TestParse(t *testing.T) {
+	data := []byte(`
+terms:
+  - term: 2026-2027-1
+    start: 2026-09-14
+    weeks: 16
+    holidays:
+      - name: 国庆节
+        start: 2026-10-01
+        end: 2026-10-07
+    makeupDays:
+      - date: 2026-10-10
+        follows: 2026-10-07
+`)
+	terms, err := Parse(data, "yaml")
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	if len(terms) != 1 || terms[0].Term != fall2026 || terms[0].Start != D(2026, 9, 14) || len(terms[0].MakeupDays) != 1 {
+		t.Errorf("Parse() = %+v", terms[0])
+	}
+
+	if _, err := Parse([]byte(`{"terms":[{"term":"2026-2027-1","start":"2026-09-14"}]}`), "json"); err == nil {
+		t.Error("Parse() error = nil without weeks")
+	}
+}
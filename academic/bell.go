@@ -0,0 +1,103 @@
+/*
+Copyright 2021 The Go jAccount Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package academic
+
+import (
+	"time"
+)
+
+// Campus is a campus of SJTU.
+type Campus string
+
+const (
+	Minhang Campus = "minhang"
+	Xuhui   Campus = "xuhui"
+)
+
+// Section is the clock time of a class section, as offsets from midnight.
+type Section struct {
+	Start time.Duration
+	End   time.Duration
+}
+
+// BellSchedule gives the clock times of the class sections. Section n is at
+// index n-1.
+type BellSchedule []Section
+
+// Section returns the clock time of the section.
+func (b BellSchedule) Section(n int) (Section, bool) {
+	if n < 1 || n > len(b) {
+		return Section{}, false
+	}
+	return b[n-1], true
+}
+
+// At returns the section in progress at the clock time, given as an offset
+// from midnight. It reports false during breaks and outside class hours.
+func (b BellSchedule) At(clock time.Duration) (int, bool) {
+	for i, s := range b {
+		if clock >= s.Start && clock < s.End {
+			return i + 1, true
+		}
+	}
+	return 0, false
+}
+
+// Next returns the first section starting at or after the clock time. It
+// reports false after the last section has started.
+func (b BellSchedule) Next(clock time.Duration) (int, bool) {
+	for i, s := range b {
+		if s.Start >= clock {
+			return i + 1, true
+		}
+	}
+	return 0, false
+}
+
+// Clock returns the time of day of t in Shanghai as an offset from midnight.
+func Clock(t time.Time) time.Duration {
+	t = t.In(Shanghai)
+	return t.Sub(DateOf(t).Time())
+}
+
+func clock(hour, minute int) time.Duration {
+	return time.Duration(hour)*time.Hour + time.Duration(minute)*time.Minute
+}
+
+// SJTUBells is the bell schedule of undergraduate classes.
+var SJTUBells = BellSchedule{
+	{clock(8, 0), clock(8, 45)},
+	{clock(8, 55), clock(9, 40)},
+	{clock(10, 0), clock(10, 45)},
+	{clock(10, 55), clock(11, 40)},
+	{clock(12, 0), clock(12, 45)},
+	{clock(12, 55), clock(13, 40)},
+	{clock(14, 0), clock(14, 45)},
+	{clock(14, 55), clock(15, 40)},
+	{clock(16, 0), clock(16, 45)},
+	{clock(16, 55), clock(17, 40)},
+	{clock(18, 0), clock(18, 45)},
+	{clock(18, 55), clock(19, 40)},
+	{clock(20, 0), clock(20, 45)},
+	{clock(20, 55), clock(21, 40)},
+}
+
+// Bells maps campuses to their bell schedules.
+var Bells = map[Campus]BellSchedule{
+	Minhang: SJTUBells,
+	Xuhui:   SJTUBells,
+}
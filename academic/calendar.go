@@ -0,0 +1,175 @@
+/*
+Copyright 2021 The Go jAccount Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package academic
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/dyweb/go-jaccount/jaccount"
+	"gopkg.in/yaml.v3"
+)
+
+// Calendar converts between times and positions in the timetable.
+type Calendar struct {
+	Terms []*TermCalendar
+	Bells BellSchedule
+}
+
+// Default is the calendar of the current terms with the undergraduate bell
+// schedule.
+var Default = &Calendar{Terms: Terms, Bells: SJTUBells}
+
+// Position is a position in the timetable.
+type Position struct {
+	Term    jaccount.Term
+	Week    int
+	Weekday time.Weekday
+
+	// Section is the class section in progress, or 0 outside classes.
+	Section int
+}
+
+// Term returns the calendar of the term.
+func (c *Calendar) Term(term jaccount.Term) (*TermCalendar, bool) {
+	for _, t := range c.Terms {
+		if t.Term == term {
+			return t, true
+		}
+	}
+	return nil, false
+}
+
+// TermOf returns the calendar of the term whose teaching weeks contain the
+// day.
+func (c *Calendar) TermOf(d Date) (*TermCalendar, bool) {
+	for _, t := range c.Terms {
+		if t.Contains(d) {
+			return t, true
+		}
+	}
+	return nil, false
+}
+
+// Locate returns the position in the timetable of the classes held at t,
+// taking holidays and make-up days into account. It reports false if no
+// classes are held on the day.
+func (c *Calendar) Locate(t time.Time) (Position, bool) {
+	d := DateOf(t)
+	term, ok := c.TermOf(d)
+	if !ok {
+		return Position{}, false
+	}
+	week, weekday, ok := term.Teaching(d)
+	if !ok {
+		return Position{}, false
+	}
+
+	section, _ := c.Bells.At(Clock(t))
+	return Position{Term: term.Term, Week: week, Weekday: weekday, Section: section}, true
+}
+
+// Meeting is a time a class is held.
+type Meeting struct {
+	Start time.Time
+	End   time.Time
+}
+
+// Meetings returns the times the classes from section first to last of the
+// weekday in the teaching week are held, which may be none on a holiday.
+func (c *Calendar) Meetings(term jaccount.Term, week int, weekday time.Weekday, first, last int) ([]Meeting, error) {
+	t, ok := c.Term(term)
+	if !ok {
+		return nil, fmt.Errorf("no calendar for term %s", term)
+	}
+	return t.ClassMeetings(c.Bells, week, weekday, first, last)
+}
+
+// ClassMeetings is like Calendar.Meetings for the term with the bell
+// schedule.
+func (c *TermCalendar) ClassMeetings(bells BellSchedule, week int, weekday time.Weekday, first, last int) ([]Meeting, error) {
+	start, ok1 := bells.Section(first)
+	end, ok2 := bells.Section(last)
+	if !ok1 || !ok2 || last < first {
+		return nil, fmt.Errorf("sections %d-%d not in bell schedule", first, last)
+	}
+
+	var meetings []Meeting
+	for _, d := range c.Meetings(week, weekday) {
+		meetings = append(meetings, Meeting{Start: d.Time().Add(start.Start), End: d.Time().Add(end.End)})
+	}
+	return meetings, nil
+}
+
+// File is a calendar file, which lists the calendars of terms.
+type File struct {
+	Terms []*TermCalendar `json:"terms" yaml:"terms"`
+}
+
+// Parse parses a calendar file in JSON or YAML format, as given by format
+// ("json" or "yaml"). Unknown fields are rejected.
+func Parse(data []byte, format string) ([]*TermCalendar, error) {
+	var f File
+	switch strings.ToLower(format) {
+	case "json":
+		dec := json.NewDecoder(bytes.NewReader(data))
+		dec.DisallowUnknownFields()
+		if err := dec.Decode(&f); err != nil {
+			return nil, err
+		}
+	case "yaml", "yml":
+		dec := yaml.NewDecoder(bytes.NewReader(data))
+		dec.KnownFields(true)
+		if err := dec.Decode(&f); err != nil {
+			return nil, err
+		}
+	default:
+		return nil, fmt.Errorf("unsupported calendar file format %q", format)
+	}
+
+	if len(f.Terms) == 0 {
+		return nil, errors.New("no terms defined")
+	}
+	for _, t := range f.Terms {
+		if t.Weeks <= 0 {
+			return nil, fmt.Errorf("term %s: weeks must be positive", t.Term)
+		}
+	}
+	return f.Terms, nil
+}
+
+// LoadFile loads a calendar file. Files ending in .yaml or .yml are parsed as
+// YAML, others as JSON.
+func LoadFile(path string) ([]*TermCalendar, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	format := "json"
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".yaml", ".yml":
+		format = "yaml"
+	}
+	return Parse(data, format)
+}
@@ -0,0 +1,104 @@
+/*
+Copyright 2021 The Go jAccount Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package academic
+
+import (
+	"fmt"
+	"time"
+)
+
+// Shanghai is the time zone of the academic calendar. China does not observe
+// daylight saving time, so it is fixed at UTC+8 and does not rely on the
+// system time zone database.
+var Shanghai = time.FixedZone("CST", 8*60*60)
+
+// Date is a calendar day in Shanghai.
+type Date struct {
+	Year  int
+	Month time.Month
+	Day   int
+}
+
+// D returns the Date of the year, month and day.
+func D(year int, month time.Month, day int) Date {
+	return DateOf(time.Date(year, month, day, 0, 0, 0, 0, Shanghai))
+}
+
+// DateOf returns the day t falls on in Shanghai.
+func DateOf(t time.Time) Date {
+	y, m, d := t.In(Shanghai).Date()
+	return Date{y, m, d}
+}
+
+// ParseDate parses a date written as 2026-09-14.
+func ParseDate(s string) (Date, error) {
+	t, err := time.ParseInLocation("2006-01-02", s, Shanghai)
+	if err != nil {
+		return Date{}, fmt.Errorf("malformed date %q", s)
+	}
+	return DateOf(t), nil
+}
+
+// Time returns midnight of the day in Shanghai.
+func (d Date) Time() time.Time {
+	return time.Date(d.Year, d.Month, d.Day, 0, 0, 0, 0, Shanghai)
+}
+
+// Weekday returns the day of the week.
+func (d Date) Weekday() time.Weekday {
+	return d.Time().Weekday()
+}
+
+// AddDays returns the date n days after d.
+func (d Date) AddDays(n int) Date {
+	return DateOf(d.Time().AddDate(0, 0, n))
+}
+
+// Sub returns the number of days from e to d.
+func (d Date) Sub(e Date) int {
+	return int(d.Time().Sub(e.Time()).Hours() / 24)
+}
+
+// Before reports whether d is before e.
+func (d Date) Before(e Date) bool {
+	return d.Sub(e) < 0
+}
+
+// After reports whether d is after e.
+func (d Date) After(e Date) bool {
+	return d.Sub(e) > 0
+}
+
+// String returns the date written as 2026-09-14.
+func (d Date) String() string {
+	return fmt.Sprintf("%04d-%02d-%02d", d.Year, d.Month, d.Day)
+}
+
+// MarshalText implements encoding.TextMarshaler.
+func (d Date) MarshalText() ([]byte, error) {
+	return []byte(d.String()), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler.
+func (d *Date) UnmarshalText(data []byte) error {
+	date, err := ParseDate(string(data))
+	if err != nil {
+		return err
+	}
+	*d = date
+	return nil
+}
@@ -0,0 +1,35 @@
+/*
+Copyright 2021 The Go jAccount Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+/*
+Package academic does date and time arithmetic on the SJTU academic calendar.
+
+A TermCalendar describes a term: the Monday its first teaching week starts on,
+how many teaching weeks it has, the holidays on which no classes are held and
+the make-up days on which the classes of another day are held instead. It
+converts between dates and teaching weeks and weekdays. A BellSchedule gives
+the clock times of the class sections on a campus, and a Calendar ties both
+together to convert between time.Time and positions in the timetable.
+
+ComputeGPA computes grade point averages of the scores returned by
+ScoresService under the 4.3 scale of SJTU, and a Transcript groups them by term
+for export to CSV and JSON.
+
+The package ships the calendars of the current terms in Terms and the bell
+schedules of the campuses in Bells. The holidays follow the arrangement of the
+State Council; check them against the notices of the registrar.
+*/
+package academic
@@ -0,0 +1,170 @@
+/*
+Copyright 2021 The Go jAccount Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package academic
+
+import (
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/dyweb/go-jaccount/jaccount"
+)
+
+// GradeBand maps percentage scores from Min upwards to a letter grade.
+type GradeBand struct {
+	Min    float64
+	Letter string
+	Point  float64
+}
+
+// GradeScale converts grades to grade points. Bands are ordered from the
+// highest to the lowest.
+type GradeScale []GradeBand
+
+// SJTUScale is the 4.3 grade-point scale of SJTU.
+var SJTUScale = GradeScale{
+	{95, "A+", 4.3},
+	{90, "A", 4.0},
+	{85, "A-", 3.7},
+	{80, "B+", 3.3},
+	{75, "B", 3.0},
+	{70, "B-", 2.7},
+	{67, "C+", 2.3},
+	{65, "C", 2.0},
+	{62, "C-", 1.7},
+	{60, "D", 1.0},
+	{0, "F", 0},
+}
+
+// Point returns the grade point of a letter grade or percentage score. It
+// reports false for pass/fail grades and grades it does not know.
+func (s GradeScale) Point(grade string) (float64, bool) {
+	grade = strings.TrimSpace(grade)
+	for _, b := range s {
+		if strings.EqualFold(b.Letter, grade) {
+			return b.Point, true
+		}
+	}
+
+	score, err := strconv.ParseFloat(grade, 64)
+	if err != nil {
+		return 0, false
+	}
+	for _, b := range s {
+		if score >= b.Min {
+			return b.Point, true
+		}
+	}
+	return 0, false
+}
+
+// GPAOptions specifies how a GPA is computed.
+type GPAOptions struct {
+	// Scale defaults to SJTUScale.
+	Scale GradeScale
+
+	// ExcludeRetakes leaves out the scores of retaken courses.
+	ExcludeRetakes bool
+
+	// ExcludePassFail leaves pass/fail courses out of the credit totals.
+	// They never count towards the GPA itself.
+	ExcludePassFail bool
+}
+
+// GPA is a grade point average with the credits it covers.
+type GPA struct {
+	// GPA is the credit-weighted average of the grade points.
+	GPA float64 `json:"gpa"`
+
+	// Credits is the number of credits of the courses counted in the GPA.
+	Credits float64 `json:"credits"`
+
+	// EarnedCredits is the number of credits of the courses passed.
+	EarnedCredits float64 `json:"earnedCredits"`
+}
+
+// included reports whether the score is counted under the options.
+func (o *GPAOptions) included(s *jaccount.Score) bool {
+	if o.ExcludeRetakes && s.Retake {
+		return false
+	}
+	if o.ExcludePassFail && isPassFail(s) {
+		return false
+	}
+	return true
+}
+
+func (o *GPAOptions) scale() GradeScale {
+	if o.Scale == nil {
+		return SJTUScale
+	}
+	return o.Scale
+}
+
+// gradePoint returns the grade point of the score under the scale, falling
+// back to the one reported by the registrar for grades the scale does not
+// know. It reports false for pass/fail courses.
+func gradePoint(scale GradeScale, s *jaccount.Score) (float64, bool) {
+	if isPassFail(s) {
+		return 0, false
+	}
+	if point, ok := scale.Point(s.Grade); ok {
+		return point, true
+	}
+	return s.GradePoint, s.GradePoint > 0
+}
+
+func isPassFail(s *jaccount.Score) bool {
+	return s.PassFail || strings.EqualFold(s.Grade, "P") || s.Grade == "通过" || s.Grade == "不通过"
+}
+
+func passed(s *jaccount.Score, point float64, graded bool) bool {
+	if isPassFail(s) {
+		return strings.EqualFold(s.Grade, "P") || s.Grade == "通过"
+	}
+	return graded && point > 0
+}
+
+// ComputeGPA computes the GPA of the scores.
+func ComputeGPA(scores []*jaccount.Score, opts *GPAOptions) GPA {
+	if opts == nil {
+		opts = &GPAOptions{}
+	}
+	scale := opts.scale()
+
+	var gpa GPA
+	var points float64
+	for _, s := range scores {
+		if !opts.included(s) {
+			continue
+		}
+
+		point, graded := gradePoint(scale, s)
+		if graded {
+			points += point * s.Credits
+			gpa.Credits += s.Credits
+		}
+		if passed(s, point, graded) {
+			gpa.EarnedCredits += s.Credits
+		}
+	}
+
+	if gpa.Credits > 0 {
+		gpa.GPA = math.Round(points/gpa.Credits*1000) / 1000
+	}
+	return gpa
+}
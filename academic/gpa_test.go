@@ -0,0 +1,123 @@
+/*
+Copyright 2021 The Go jAccount Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package academic
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/dyweb/go-jaccount/jaccount"
+)
+
+var (
+	fall2025   = jaccount.Term{Year: 2025, Semester: jaccount.Fall}
+	spring2026 = jaccount.Term{Year: 2025, Semester: jaccount.Spring}
+)
+
+func testScores() []*jaccount.Score {
+	return []*jaccount.Score{
+		{CourseCode: "MA1201", CourseName: "高等数学", Term: fall2025, Credits: 6, Grade: "F"},
+		{CourseCode: "CS1501", CourseName: "程序设计", Term: fall2025, Credits: 4, Grade: "96"},
+		{CourseCode: "PE001", CourseName: "体育", Term: fall2025, Credits: 1, Grade: "P", PassFail: true},
+		{CourseCode: "MA1201", CourseName: "高等数学", Term: spring2026, Credits: 6, Grade: "B+", Retake: true},
+		{CourseCode: "PH1001", CourseName: "大学物理", Term: spring2026, Credits: 4, Grade: "A-"},
+	}
+}
+
+func TestGradeScale_Point(t *testing.T) {
+	tests := []struct {
+		grade string
+		point float64
+		ok    bool
+	}{
+		{"A+", 4.3, true},
+		{"b-", 2.7, true},
+		{"95", 4.3, true},
+		{"84.5", 3.3, true},
+		{"59", 0, true},
+		{"P", 0, false},
+		{"优秀", 0, false},
+	}
+	for _, tt := range tests {
+		point, ok := SJTUScale.Point(tt.grade)
+		if point != tt.point || ok != tt.ok {
+			t.Errorf("Point(%q) = %v, %v, want %v, %v", tt.grade, point, ok, tt.point, tt.ok)
+		}
+	}
+}
+
+func TestComputeGPA(t *testing.T) {
+	scores := testScores()
+
+	// (0*6 + 4.3*4 + 3.3*6 + 3.7*4) / 20
+	got := ComputeGPA(scores, nil)
+	if got.GPA != 2.59 || got.Credits != 20 || got.EarnedCredits != 15 {
+		t.Errorf("ComputeGPA() = %+v", got)
+	}
+
+	got = ComputeGPA(scores, &GPAOptions{ExcludeRetakes: true})
+	if got.GPA != 2.286 || got.Credits != 14 {
+		t.Errorf("ComputeGPA(ExcludeRetakes) = %+v", got)
+	}
+
+	got = ComputeGPA(scores, &GPAOptions{ExcludePassFail: true})
+	if got.GPA != 2.59 || got.EarnedCredits != 14 {
+		t.Errorf("ComputeGPA(ExcludePassFail) = %+v", got)
+	}
+}
+
+func TestTranscript(t *testing.T) {
+	transcript := NewTranscript(testScores(), nil)
+	if len(transcript.Terms) != 2 || transcript.Terms[0].Term != fall2025 {
+		t.Fatalf("NewTranscript() terms = %+v", transcript.Terms)
+	}
+	if got := transcript.Terms[1].GPA.GPA; got != 3.46 {
+		t.Errorf("spring GPA = %v, want 3.46", got)
+	}
+
+	var buf bytes.Buffer
+	if err := transcript.WriteCSV(&buf); err != nil {
+		t.Fatalf("Transcript.WriteCSV() error = %v", err)
+	}
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 9 {
+		t.Fatalf("Transcript.WriteCSV() wrote %d lines, want 9:\n%s", len(lines), buf.String())
+	}
+	for i, want := range map[int]string{
+		2: "2025-2026-1,CS1501,程序设计,4,96,4.3,,false",
+		3: "2025-2026-1,PE001,体育,1,P,,,false",
+		8: ",,Cumulative GPA,20,,2.59,,",
+	} {
+		if lines[i] != want {
+			t.Errorf("line %d = %q, want %q", i, lines[i], want)
+		}
+	}
+
+	buf.Reset()
+	if err := transcript.WriteJSON(&buf); err != nil {
+		t.Fatalf("Transcript.WriteJSON() error = %v", err)
+	}
+	var decoded Transcript
+	if err := json.Unmarshal(buf.Bytes(), &decoded); err != nil {
+		t.Fatalf("json.Unmarshal() error = %v", err)
+	}
+	if decoded.Cumulative != transcript.Cumulative || len(decoded.Terms) != 2 || decoded.Terms[1].Scores[0].Grade != "B+" {
+		t.Errorf("Transcript.WriteJSON() round trip = %+v", decoded)
+	}
+}
@@ -0,0 +1,144 @@
+/*
+Copyright 2021 The Go jAccount Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package academic
+
+import (
+	"time"
+
+	"github.com/dyweb/go-jaccount/jaccount"
+)
+
+// Holiday is a period without classes, from Start to End inclusive.
+type Holiday struct {
+	Name  string `json:"name" yaml:"name"`
+	Start Date   `json:"start" yaml:"start"`
+	End   Date   `json:"end" yaml:"end"`
+}
+
+// Contains reports whether the day falls in the holiday.
+func (h Holiday) Contains(d Date) bool {
+	return !d.Before(h.Start) && !d.After(h.End)
+}
+
+// MakeupDay is a day, usually a weekend, on which the classes of another day,
+// usually in a holiday, are held.
+type MakeupDay struct {
+	Date    Date `json:"date" yaml:"date"`
+	Follows Date `json:"follows" yaml:"follows"`
+}
+
+// TermCalendar is the calendar of a term.
+type TermCalendar struct {
+	Term jaccount.Term `json:"term" yaml:"term"`
+
+	// Start is the Monday of the first teaching week. Any other day is
+	// taken to mean the Monday of its week.
+	Start Date `json:"start" yaml:"start"`
+
+	// Weeks is the number of teaching weeks.
+	Weeks int `json:"weeks" yaml:"weeks"`
+
+	Holidays   []Holiday   `json:"holidays,omitempty" yaml:"holidays,omitempty"`
+	MakeupDays []MakeupDay `json:"makeupDays,omitempty" yaml:"makeupDays,omitempty"`
+}
+
+// monday returns the Monday of the first teaching week.
+func (c *TermCalendar) monday() Date {
+	return c.Start.AddDays(-daysSinceMonday(c.Start.Weekday()))
+}
+
+func daysSinceMonday(weekday time.Weekday) int {
+	return (int(weekday) + 6) % 7
+}
+
+// Date returns the day of the teaching week falling on the weekday.
+func (c *TermCalendar) Date(week int, weekday time.Weekday) Date {
+	return c.monday().AddDays(7*(week-1) + daysSinceMonday(weekday))
+}
+
+// Week returns the teaching week the day falls in and its weekday. It reports
+// false if the day is outside the teaching weeks.
+func (c *TermCalendar) Week(d Date) (int, time.Weekday, bool) {
+	days := d.Sub(c.monday())
+	if days < 0 || days >= 7*c.Weeks {
+		return 0, 0, false
+	}
+	return days/7 + 1, d.Weekday(), true
+}
+
+// Contains reports whether the day falls in the teaching weeks.
+func (c *TermCalendar) Contains(d Date) bool {
+	_, _, ok := c.Week(d)
+	return ok
+}
+
+// Holiday returns the holiday the day falls in.
+func (c *TermCalendar) Holiday(d Date) (Holiday, bool) {
+	for _, h := range c.Holidays {
+		if h.Contains(d) {
+			return h, true
+		}
+	}
+	return Holiday{}, false
+}
+
+// Teaching returns the teaching week and weekday whose classes are held on
+// the day. On a make-up day, they are those of the day it follows. It reports
+// false on holidays, on days whose classes moved to a make-up day and outside
+// the teaching weeks.
+func (c *TermCalendar) Teaching(d Date) (int, time.Weekday, bool) {
+	for _, m := range c.MakeupDays {
+		if m.Date == d {
+			return c.Week(m.Follows)
+		}
+	}
+	if _, ok := c.Holiday(d); ok || c.madeUp(d) {
+		return 0, 0, false
+	}
+	return c.Week(d)
+}
+
+// Meetings returns the days on which the classes of the weekday in the
+// teaching week are held: the day itself unless it is a holiday, or the
+// make-up days following it.
+func (c *TermCalendar) Meetings(week int, weekday time.Weekday) []Date {
+	d := c.Date(week, weekday)
+
+	var days []Date
+	for _, m := range c.MakeupDays {
+		if m.Follows == d {
+			days = append(days, m.Date)
+		}
+	}
+	if len(days) > 0 {
+		return days
+	}
+
+	if _, ok := c.Holiday(d); ok {
+		return nil
+	}
+	return []Date{d}
+}
+
+func (c *TermCalendar) madeUp(d Date) bool {
+	for _, m := range c.MakeupDays {
+		if m.Follows == d {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,66 @@
+/*
+Copyright 2021 The Go jAccount Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package academic
+
+import (
+	"github.com/dyweb/go-jaccount/jaccount"
+)
+
+// Terms are the calendars of the current terms.
+var Terms = []*TermCalendar{
+	{
+		Term:  jaccount.Term{Year: 2025, Semester: jaccount.Fall},
+		Start: D(2025, 9, 15),
+		Weeks: 16,
+		Holidays: []Holiday{
+			{"国庆节、中秋节", D(2025, 10, 1), D(2025, 10, 8)},
+			{"元旦", D(2026, 1, 1), D(2026, 1, 3)},
+		},
+		MakeupDays: []MakeupDay{
+			{D(2025, 9, 28), D(2025, 10, 7)},
+			{D(2025, 10, 11), D(2025, 10, 8)},
+			{D(2026, 1, 4), D(2026, 1, 2)},
+		},
+	},
+	{
+		Term:  jaccount.Term{Year: 2025, Semester: jaccount.Spring},
+		Start: D(2026, 3, 2),
+		Weeks: 16,
+		Holidays: []Holiday{
+			{"清明节", D(2026, 4, 4), D(2026, 4, 6)},
+			{"劳动节", D(2026, 5, 1), D(2026, 5, 5)},
+			{"端午节", D(2026, 6, 19), D(2026, 6, 21)},
+		},
+		MakeupDays: []MakeupDay{
+			{D(2026, 5, 9), D(2026, 5, 5)},
+		},
+	},
+	{
+		Term:  jaccount.Term{Year: 2026, Semester: jaccount.Fall},
+		Start: D(2026, 9, 14),
+		Weeks: 16,
+		Holidays: []Holiday{
+			{"中秋节", D(2026, 9, 25), D(2026, 9, 27)},
+			{"国庆节", D(2026, 10, 1), D(2026, 10, 7)},
+			{"元旦", D(2027, 1, 1), D(2027, 1, 3)},
+		},
+		MakeupDays: []MakeupDay{
+			{D(2026, 9, 20), D(2026, 10, 6)},
+			{D(2026, 10, 10), D(2026, 10, 7)},
+		},
+	},
+}
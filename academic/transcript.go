@@ -0,0 +1,117 @@
+/*
+Copyright 2021 The Go jAccount Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package academic
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"io"
+	"sort"
+	"strconv"
+
+	"github.com/dyweb/go-jaccount/jaccount"
+)
+
+// Transcript lists the scores of a student by term with the GPAs.
+type Transcript struct {
+	Terms      []*TermRecord `json:"terms"`
+	Cumulative GPA           `json:"cumulative"`
+
+	scale GradeScale
+}
+
+// TermRecord is the scores and GPA of a term in a Transcript.
+type TermRecord struct {
+	Term   jaccount.Term     `json:"term"`
+	Scores []*jaccount.Score `json:"scores"`
+	GPA    GPA               `json:"gpa"`
+}
+
+// NewTranscript groups the scores by term, in order, and computes the term
+// and cumulative GPAs. Scores left out under the options are left out of the
+// transcript as well.
+func NewTranscript(scores []*jaccount.Score, opts *GPAOptions) *Transcript {
+	if opts == nil {
+		opts = &GPAOptions{}
+	}
+
+	byTerm := make(map[jaccount.Term]*TermRecord)
+	var included []*jaccount.Score
+	for _, s := range scores {
+		if !opts.included(s) {
+			continue
+		}
+		included = append(included, s)
+
+		r, ok := byTerm[s.Term]
+		if !ok {
+			r = &TermRecord{Term: s.Term}
+			byTerm[s.Term] = r
+		}
+		r.Scores = append(r.Scores, s)
+	}
+
+	t := &Transcript{Cumulative: ComputeGPA(included, opts), scale: opts.scale()}
+	for _, r := range byTerm {
+		r.GPA = ComputeGPA(r.Scores, opts)
+		t.Terms = append(t.Terms, r)
+	}
+	sort.Slice(t.Terms, func(i, j int) bool {
+		a, b := t.Terms[i].Term, t.Terms[j].Term
+		if a.Year != b.Year {
+			return a.Year < b.Year
+		}
+		return a.Semester < b.Semester
+	})
+	return t
+}
+
+// WriteJSON writes the transcript as JSON.
+func (t *Transcript) WriteJSON(w io.Writer) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(t)
+}
+
+// WriteCSV writes the transcript as CSV, one row per score, followed by a row
+// with the GPA of every term and a row with the cumulative GPA.
+func (t *Transcript) WriteCSV(w io.Writer) error {
+	cw := csv.NewWriter(w)
+	cw.Write([]string{"term", "course_code", "course_name", "credits", "grade", "grade_point", "exam_type", "retake"})
+
+	for _, r := range t.Terms {
+		for _, s := range r.Scores {
+			point := ""
+			if p, ok := gradePoint(t.scale, s); ok {
+				point = formatFloat(p)
+			}
+			cw.Write([]string{
+				r.Term.String(), s.CourseCode, s.CourseName, formatFloat(s.Credits),
+				s.Grade, point, s.ExamType, strconv.FormatBool(s.Retake),
+			})
+		}
+		cw.Write([]string{r.Term.String(), "", "GPA", formatFloat(r.GPA.Credits), "", formatFloat(r.GPA.GPA), "", ""})
+	}
+	cw.Write([]string{"", "", "Cumulative GPA", formatFloat(t.Cumulative.Credits), "", formatFloat(t.Cumulative.GPA), "", ""})
+
+	cw.Flush()
+	return cw.Error()
+}
+
+func formatFloat(f float64) string {
+	return strconv.FormatFloat(f, 'f', -1, 64)
+}
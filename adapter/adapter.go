@@ -0,0 +1,120 @@
+/*
+Copyright 2021 The Go jAccount Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+/*
+Package adapter is the shared core of the web framework adapters in its
+subpackages. It assembles the login, callback and logout handlers of a
+session.Manager and the middleware of the session, bearer and policy packages
+as plain net/http handlers, which the adapters then mount using the idioms of
+each framework, so that they all behave the same.
+*/
+package adapter
+
+import (
+	"net/http"
+
+	"github.com/dyweb/go-jaccount/bearer"
+	"github.com/dyweb/go-jaccount/policy"
+	"github.com/dyweb/go-jaccount/session"
+)
+
+// Default paths of the routes.
+const (
+	DefaultLoginPath    = "/login"
+	DefaultCallbackPath = "/callback"
+	DefaultLogoutPath   = "/logout"
+)
+
+// Route is an HTTP route to be mounted by an adapter.
+type Route struct {
+	Methods []string
+	Path    string
+	Handler http.Handler
+}
+
+// Config configures the routes and middleware of an adapter.
+type Config struct {
+	// Session manages the login sessions. It is required for the routes and
+	// the session middleware.
+	Session *session.Manager
+
+	// Bearer authenticates bearer tokens. It is required for the bearer
+	// middleware.
+	Bearer *bearer.Authenticator
+
+	// LoginPath, CallbackPath and LogoutPath default to "/login",
+	// "/callback" and "/logout". The path of Session.Config.RedirectURL must
+	// match CallbackPath.
+	LoginPath    string
+	CallbackPath string
+	LogoutPath   string
+}
+
+// Routes returns the login, callback and logout routes. Logout only accepts
+// POST, as does Session.LogoutHandler.
+func (c *Config) Routes() []Route {
+	return []Route{
+		{Methods: []string{http.MethodGet}, Path: c.loginPath(), Handler: c.Session.LoginHandler()},
+		{Methods: []string{http.MethodGet}, Path: c.callbackPath(), Handler: c.Session.CallbackHandler()},
+		{Methods: []string{http.MethodPost}, Path: c.logoutPath(), Handler: c.Session.LogoutHandler()},
+	}
+}
+
+// SessionMiddleware loads the session, if any, into the request context.
+func (c *Config) SessionMiddleware() func(http.Handler) http.Handler {
+	return c.Session.Middleware
+}
+
+// RequireLogin sends users without a session to the login route.
+func (c *Config) RequireLogin() func(http.Handler) http.Handler {
+	m := *c.Session
+	if m.LoginURL == "" {
+		m.LoginURL = c.loginPath()
+	}
+	return m.RequireLogin
+}
+
+// BearerMiddleware rejects requests without a valid bearer token.
+func (c *Config) BearerMiddleware() func(http.Handler) http.Handler {
+	return c.Bearer.Handler
+}
+
+// Require enforces the policy on requests. It must come after one of the
+// other middleware.
+func (c *Config) Require(p policy.Policy) func(http.Handler) http.Handler {
+	return policy.Require(p)
+}
+
+func (c *Config) loginPath() string {
+	if c.LoginPath == "" {
+		return DefaultLoginPath
+	}
+	return c.LoginPath
+}
+
+func (c *Config) callbackPath() string {
+	if c.CallbackPath == "" {
+		return DefaultCallbackPath
+	}
+	return c.CallbackPath
+}
+
+func (c *Config) logoutPath() string {
+	if c.LogoutPath == "" {
+		return DefaultLogoutPath
+	}
+	return c.LogoutPath
+}
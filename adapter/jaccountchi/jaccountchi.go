@@ -0,0 +1,87 @@
+/*
+Copyright 2021 The Go jAccount Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package jaccountchi adapts jAccount login and authentication to chi routers.
+package jaccountchi
+
+import (
+	"net/http"
+
+	"github.com/dyweb/go-jaccount/adapter"
+	"github.com/dyweb/go-jaccount/jaccount"
+	"github.com/dyweb/go-jaccount/policy"
+	"github.com/dyweb/go-jaccount/session"
+	"github.com/go-chi/chi/v5"
+)
+
+// Adapter mounts jAccount routes and middleware on chi routers.
+type Adapter struct {
+	config *adapter.Config
+}
+
+// New returns an Adapter for the configuration.
+func New(config *adapter.Config) *Adapter {
+	return &Adapter{config: config}
+}
+
+// Mount registers the login, callback and logout routes on r.
+func (a *Adapter) Mount(r chi.Router) {
+	for _, route := range a.config.Routes() {
+		for _, method := range route.Methods {
+			r.Method(method, route.Path, route.Handler)
+		}
+	}
+}
+
+// Session loads the session, if any, into the request context.
+func (a *Adapter) Session() func(http.Handler) http.Handler {
+	return a.config.SessionMiddleware()
+}
+
+// RequireLogin sends users without a session to the login route.
+func (a *Adapter) RequireLogin() func(http.Handler) http.Handler {
+	return a.config.RequireLogin()
+}
+
+// Bearer rejects requests without a valid bearer token.
+func (a *Adapter) Bearer() func(http.Handler) http.Handler {
+	return a.config.BearerMiddleware()
+}
+
+// Require enforces the policy on requests.
+func (a *Adapter) Require(p policy.Policy) func(http.Handler) http.Handler {
+	return a.config.Require(p)
+}
+
+// IDToken returns the ID token of the logged-in user.
+func IDToken(r *http.Request) (*jaccount.IDToken, bool) {
+	return jaccount.IDTokenFromContext(r.Context())
+}
+
+// Profile returns the profile of the user authenticated by a bearer token.
+func Profile(r *http.Request) (*jaccount.Profile, bool) {
+	return jaccount.ProfileFromContext(r.Context())
+}
+
+// TokenInfo returns the introspected bearer token of the request.
+func TokenInfo(r *http.Request) (*jaccount.TokenInfo, bool) {
+	return jaccount.TokenInfoFromContext(r.Context())
+}
+
+// Session returns the session of the logged-in user.
+func Session(r *http.Request) (*session.Session, bool) {
+	return session.FromContext(r.Context())
+}
@@ -0,0 +1,89 @@
+/*
+Copyright 2021 The Go jAccount Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package jaccountecho adapts jAccount login and authentication to Echo.
+package jaccountecho
+
+import (
+	"github.com/dyweb/go-jaccount/adapter"
+	"github.com/dyweb/go-jaccount/jaccount"
+	"github.com/dyweb/go-jaccount/policy"
+	"github.com/dyweb/go-jaccount/session"
+	"github.com/labstack/echo/v4"
+)
+
+// Router is the part of *echo.Echo and *echo.Group that routes are mounted
+// on.
+type Router interface {
+	Match(methods []string, path string, handler echo.HandlerFunc, middleware ...echo.MiddlewareFunc) []*echo.Route
+}
+
+// Adapter mounts jAccount routes and middleware on Echo instances.
+type Adapter struct {
+	config *adapter.Config
+}
+
+// New returns an Adapter for the configuration.
+func New(config *adapter.Config) *Adapter {
+	return &Adapter{config: config}
+}
+
+// Mount registers the login, callback and logout routes on r.
+func (a *Adapter) Mount(r Router) {
+	for _, route := range a.config.Routes() {
+		r.Match(route.Methods, route.Path, echo.WrapHandler(route.Handler))
+	}
+}
+
+// Session loads the session, if any, into the request context.
+func (a *Adapter) Session() echo.MiddlewareFunc {
+	return echo.WrapMiddleware(a.config.SessionMiddleware())
+}
+
+// RequireLogin sends users without a session to the login route.
+func (a *Adapter) RequireLogin() echo.MiddlewareFunc {
+	return echo.WrapMiddleware(a.config.RequireLogin())
+}
+
+// Bearer rejects requests without a valid bearer token.
+func (a *Adapter) Bearer() echo.MiddlewareFunc {
+	return echo.WrapMiddleware(a.config.BearerMiddleware())
+}
+
+// Require enforces the policy on requests.
+func (a *Adapter) Require(p policy.Policy) echo.MiddlewareFunc {
+	return echo.WrapMiddleware(a.config.Require(p))
+}
+
+// IDToken returns the ID token of the logged-in user.
+func IDToken(c echo.Context) (*jaccount.IDToken, bool) {
+	return jaccount.IDTokenFromContext(c.Request().Context())
+}
+
+// Profile returns the profile of the user authenticated by a bearer token.
+func Profile(c echo.Context) (*jaccount.Profile, bool) {
+	return jaccount.ProfileFromContext(c.Request().Context())
+}
+
+// TokenInfo returns the introspected bearer token of the request.
+func TokenInfo(c echo.Context) (*jaccount.TokenInfo, bool) {
+	return jaccount.TokenInfoFromContext(c.Request().Context())
+}
+
+// Session returns the session of the logged-in user.
+func Session(c echo.Context) (*session.Session, bool) {
+	return session.FromContext(c.Request().Context())
+}
@@ -0,0 +1,105 @@
+/*
+Copyright 2021 The Go jAccount Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package jaccountgin adapts jAccount login and authentication to Gin.
+package jaccountgin
+
+import (
+	"net/http"
+
+	"github.com/dyweb/go-jaccount/adapter"
+	"github.com/dyweb/go-jaccount/jaccount"
+	"github.com/dyweb/go-jaccount/policy"
+	"github.com/dyweb/go-jaccount/session"
+	"github.com/gin-gonic/gin"
+)
+
+// Adapter mounts jAccount routes and middleware on Gin engines.
+type Adapter struct {
+	config *adapter.Config
+}
+
+// New returns an Adapter for the configuration.
+func New(config *adapter.Config) *Adapter {
+	return &Adapter{config: config}
+}
+
+// Mount registers the login, callback and logout routes on r.
+func (a *Adapter) Mount(r gin.IRoutes) {
+	for _, route := range a.config.Routes() {
+		for _, method := range route.Methods {
+			r.Handle(method, route.Path, gin.WrapH(route.Handler))
+		}
+	}
+}
+
+// Session loads the session, if any, into the request context.
+func (a *Adapter) Session() gin.HandlerFunc {
+	return wrap(a.config.SessionMiddleware())
+}
+
+// RequireLogin sends users without a session to the login route.
+func (a *Adapter) RequireLogin() gin.HandlerFunc {
+	return wrap(a.config.RequireLogin())
+}
+
+// Bearer rejects requests without a valid bearer token.
+func (a *Adapter) Bearer() gin.HandlerFunc {
+	return wrap(a.config.BearerMiddleware())
+}
+
+// Require enforces the policy on requests.
+func (a *Adapter) Require(p policy.Policy) gin.HandlerFunc {
+	return wrap(a.config.Require(p))
+}
+
+// wrap converts net/http middleware to a Gin handler. The rest of the chain
+// runs inside the middleware with the request it passes on; if the middleware
+// responds on its own, the chain is aborted.
+func wrap(middleware func(http.Handler) http.Handler) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		called := false
+		middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			called = true
+			c.Request = r
+			c.Next()
+		})).ServeHTTP(c.Writer, c.Request)
+
+		if !called {
+			c.Abort()
+		}
+	}
+}
+
+// IDToken returns the ID token of the logged-in user.
+func IDToken(c *gin.Context) (*jaccount.IDToken, bool) {
+	return jaccount.IDTokenFromContext(c.Request.Context())
+}
+
+// Profile returns the profile of the user authenticated by a bearer token.
+func Profile(c *gin.Context) (*jaccount.Profile, bool) {
+	return jaccount.ProfileFromContext(c.Request.Context())
+}
+
+// TokenInfo returns the introspected bearer token of the request.
+func TokenInfo(c *gin.Context) (*jaccount.TokenInfo, bool) {
+	return jaccount.TokenInfoFromContext(c.Request.Context())
+}
+
+// Session returns the session of the logged-in user.
+func Session(c *gin.Context) (*session.Session, bool) {
+	return session.FromContext(c.Request.Context())
+}
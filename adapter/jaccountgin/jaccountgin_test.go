@@ -0,0 +1,122 @@
+/*
+Copyright 2021 The Go jAccount Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package jaccountgin
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/dyweb/go-jaccount/adapter"
+	"github.com/dyweb/go-jaccount/bearer"
+	"github.com/dyweb/go-jaccount/jaccount"
+	"github.com/dyweb/go-jaccount/policy"
+	"github.com/dyweb/go-jaccount/session"
+	"github.com/gin-gonic/gin"
+	"golang.org/x/oauth2"
+)
+
+func TestAdapter_Bearer(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	a := New(&adapter.Config{
+		Bearer: &bearer.Authenticator{
+			Validator: bearer.ValidatorFunc(func(ctx context.Context, token string) (*bearer.Result, error) {
+				if token != "valid" {
+					return nil, &bearer.Error{Code: bearer.ErrorInvalidToken}
+				}
+				return &bearer.Result{Profile: &jaccount.Profile{Account: "test", UserType: "student"}}, nil
+			}),
+		},
+	})
+
+	r := gin.New()
+	api := r.Group("/api", a.Bearer(), a.Require(policy.RequireType(jaccount.STUDENT)))
+	api.GET("/me", func(c *gin.Context) {
+		profile, ok := Profile(c)
+		if !ok {
+			t.Errorf("Profile() ok = false")
+			return
+		}
+		c.String(http.StatusOK, profile.Account)
+	})
+
+	tests := []struct {
+		name       string
+		token      string
+		wantStatus int
+		wantBody   string
+	}{
+		{"valid", "valid", http.StatusOK, "test"},
+		{"invalid", "invalid", http.StatusUnauthorized, "Unauthorized\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/api/me", nil)
+			req.Header.Set("Authorization", "Bearer "+tt.token)
+
+			rec := httptest.NewRecorder()
+			r.ServeHTTP(rec, req)
+
+			if rec.Code != tt.wantStatus || rec.Body.String() != tt.wantBody {
+				t.Errorf("GET /api/me = %d %q, want %d %q", rec.Code, rec.Body, tt.wantStatus, tt.wantBody)
+			}
+		})
+	}
+}
+
+func TestAdapter_Mount(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	a := New(&adapter.Config{
+		Session: &session.Manager{
+			Config: &oauth2.Config{
+				ClientID:    "client",
+				Endpoint:    jaccount.Endpoint,
+				RedirectURL: "http://localhost/callback",
+			},
+			Store: &session.ServerStore{Backend: &session.MemoryBackend{}},
+		},
+	})
+
+	r := gin.New()
+	a.Mount(r)
+
+	tests := []struct {
+		name       string
+		method     string
+		path       string
+		wantStatus int
+	}{
+		{"login", http.MethodGet, "/login", http.StatusFound},
+		{"logout", http.MethodPost, "/logout", http.StatusFound},
+		{"logout with GET", http.MethodGet, "/logout", http.StatusNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			r.ServeHTTP(rec, httptest.NewRequest(tt.method, tt.path, nil))
+
+			if rec.Code != tt.wantStatus {
+				t.Errorf("%s %s status = %d, want %d", tt.method, tt.path, rec.Code, tt.wantStatus)
+			}
+		})
+	}
+}
@@ -0,0 +1,252 @@
+/*
+Copyright 2021 The Go jAccount Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package bearer
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/dyweb/go-jaccount/jaccount"
+)
+
+// Error codes defined in RFC 6750.
+const (
+	ErrorInvalidRequest    = "invalid_request"
+	ErrorInvalidToken      = "invalid_token"
+	ErrorInsufficientScope = "insufficient_scope"
+)
+
+// Error is an authentication error reported to the client in the
+// WWW-Authenticate response header.
+type Error struct {
+	// Code is one of the RFC 6750 error codes, or empty if the request
+	// carries no authentication information at all.
+	Code        string
+	Description string
+
+	// Scope lists the scopes required to access the resource.
+	Scope string
+}
+
+func (e *Error) Error() string {
+	if e.Code == "" {
+		return "bearer token required"
+	}
+	if e.Description == "" {
+		return e.Code
+	}
+	return fmt.Sprintf("%s: %s", e.Code, e.Description)
+}
+
+// StatusCode returns the HTTP status code of the error.
+func (e *Error) StatusCode() int {
+	switch e.Code {
+	case ErrorInvalidRequest:
+		return http.StatusBadRequest
+	case ErrorInsufficientScope:
+		return http.StatusForbidden
+	default:
+		return http.StatusUnauthorized
+	}
+}
+
+// Challenge returns the value of the WWW-Authenticate header for the error.
+func (e *Error) Challenge(realm string) string {
+	params := []string{}
+	if realm != "" {
+		params = append(params, fmt.Sprintf("realm=%q", realm))
+	}
+	if e.Code != "" {
+		params = append(params, fmt.Sprintf("error=%q", e.Code))
+	}
+	if e.Description != "" {
+		params = append(params, fmt.Sprintf("error_description=%q", e.Description))
+	}
+	if e.Scope != "" {
+		params = append(params, fmt.Sprintf("scope=%q", e.Scope))
+	}
+
+	if len(params) == 0 {
+		return "Bearer"
+	}
+	return "Bearer " + strings.Join(params, ", ")
+}
+
+// Result is the identity of the user a bearer token belongs to. Which fields
+// are set depends on the Validator.
+type Result struct {
+	TokenInfo *jaccount.TokenInfo
+	Profile   *jaccount.Profile
+	IDToken   *jaccount.IDToken
+
+	// Scopes are the scopes granted to the token, or nil if unknown.
+	Scopes jaccount.Scopes
+
+	// Expiry is when the token expires, or the zero time if unknown. Cached
+	// results never outlive the token.
+	Expiry time.Time
+}
+
+// NewContext returns a copy of ctx that carries the identity in r.
+func (r *Result) NewContext(ctx context.Context) context.Context {
+	if r.TokenInfo != nil {
+		ctx = jaccount.ContextWithTokenInfo(ctx, r.TokenInfo)
+	}
+	if r.Profile != nil {
+		ctx = jaccount.ContextWithProfile(ctx, r.Profile)
+	}
+	if r.IDToken != nil {
+		ctx = jaccount.ContextWithIDToken(ctx, r.IDToken)
+	}
+	return ctx
+}
+
+const defaultCacheTTL = time.Minute
+
+// Authenticator authenticates requests with bearer tokens.
+type Authenticator struct {
+	// Validator validates the bearer tokens.
+	Validator Validator
+
+	// Realm is reported in the WWW-Authenticate header.
+	Realm string
+
+	// RequiredScopes are the scopes the token must have been granted. If
+	// set, tokens are rejected when the validator cannot report the granted
+	// scopes, as with IDTokenVerifier and ProfileLookup.
+	RequiredScopes []string
+
+	// CacheTTL is how long a validated token is cached. Defaults to one
+	// minute; a negative value disables caching.
+	CacheTTL time.Duration
+
+	// CacheSize bounds the number of cached tokens. Defaults to 10000.
+	CacheSize int
+
+	cache cache
+}
+
+// Handler returns middleware that authenticates requests before passing them
+// to next. Requests without a valid token are rejected.
+func (a *Authenticator) Handler(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		result, err := a.AuthenticateRequest(r)
+		if err != nil {
+			a.WriteError(w, err)
+			return
+		}
+
+		next.ServeHTTP(w, r.WithContext(result.NewContext(r.Context())))
+	})
+}
+
+// AuthenticateRequest extracts the bearer token of r and authenticates it.
+func (a *Authenticator) AuthenticateRequest(r *http.Request) (*Result, error) {
+	token, err := TokenFromRequest(r)
+	if err != nil {
+		return nil, err
+	}
+	return a.Authenticate(r.Context(), token)
+}
+
+// Authenticate validates the token, consulting the cache first.
+func (a *Authenticator) Authenticate(ctx context.Context, token string) (*Result, error) {
+	if token == "" {
+		return nil, &Error{}
+	}
+
+	result, ok := a.cache.get(token)
+	if !ok {
+		var err error
+		result, err = a.Validator.Validate(ctx, token)
+		if err != nil {
+			return nil, err
+		}
+
+		if a.CacheTTL >= 0 {
+			ttl := a.CacheTTL
+			if ttl == 0 {
+				ttl = defaultCacheTTL
+			}
+			a.cache.put(token, result, ttl, a.CacheSize)
+		}
+	}
+
+	if len(a.RequiredScopes) > 0 {
+		if result.Scopes == nil {
+			return nil, &Error{
+				Code:        ErrorInsufficientScope,
+				Description: "scopes of the token are unknown",
+				Scope:       strings.Join(a.RequiredScopes, " "),
+			}
+		}
+		if missing := result.Scopes.Missing(jaccount.NewScopes(a.RequiredScopes...)); len(missing) > 0 {
+			return nil, &Error{
+				Code:        ErrorInsufficientScope,
+				Description: "missing scopes " + strings.Join(missing, " "),
+				Scope:       strings.Join(a.RequiredScopes, " "),
+			}
+		}
+	}
+
+	return result, nil
+}
+
+// WriteError writes the response for an authentication error. Errors other
+// than *Error are treated as a failure to validate the token, for example
+// when the introspection endpoint is unreachable.
+func (a *Authenticator) WriteError(w http.ResponseWriter, err error) {
+	var e *Error
+	if !errors.As(err, &e) {
+		http.Error(w, "failed to validate bearer token", http.StatusServiceUnavailable)
+		return
+	}
+
+	w.Header().Set("WWW-Authenticate", e.Challenge(a.Realm))
+	http.Error(w, http.StatusText(e.StatusCode()), e.StatusCode())
+}
+
+// TokenFromRequest returns the bearer token in the Authorization header of r.
+func TokenFromRequest(r *http.Request) (string, error) {
+	header := r.Header.Values("Authorization")
+	if len(header) == 0 {
+		return "", &Error{}
+	}
+	if len(header) > 1 {
+		return "", &Error{Code: ErrorInvalidRequest, Description: "multiple authorization headers"}
+	}
+
+	return ParseAuthorization(header[0])
+}
+
+// ParseAuthorization returns the token of a "Bearer" authorization value.
+func ParseAuthorization(value string) (string, error) {
+	const prefix = "bearer "
+	if len(value) < len(prefix) || !strings.EqualFold(value[:len(prefix)], prefix) {
+		return "", &Error{}
+	}
+
+	token := strings.TrimSpace(value[len(prefix):])
+	if token == "" || strings.ContainsAny(token, " \t") {
+		return "", &Error{Code: ErrorInvalidRequest, Description: "malformed bearer token"}
+	}
+	return token, nil
+}
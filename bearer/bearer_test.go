@@ -0,0 +1,192 @@
+/*
+Copyright 2021 The Go jAccount Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package bearer
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/dyweb/go-jaccount/jaccount"
+	"gopkg.in/square/go-jose.v2"
+	"gopkg.in/square/go-jose.v2/jwt"
+)
+
+func TestAuthenticator_Handler(t *testing.T) {
+	calls := 0
+	authenticator := &Authenticator{
+		Realm:          "api",
+		RequiredScopes: []string{jaccount.ScopeEssential},
+		Validator: ValidatorFunc(func(ctx context.Context, token string) (*Result, error) {
+			calls++
+			switch token {
+			case "valid":
+				return &Result{
+					Profile: &jaccount.Profile{Account: "test"},
+					Scopes:  jaccount.NewScopes(jaccount.ScopeBasic, jaccount.ScopeEssential),
+				}, nil
+			case "basic":
+				return &Result{Scopes: jaccount.NewScopes(jaccount.ScopeBasic)}, nil
+			case "unknown":
+				return &Result{Profile: &jaccount.Profile{Account: "test"}}, nil
+			default:
+				return nil, &Error{Code: ErrorInvalidToken}
+			}
+		}),
+	}
+
+	handler := authenticator.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		profile, ok := jaccount.ProfileFromContext(r.Context())
+		if !ok {
+			t.Errorf("jaccount.ProfileFromContext() ok = false")
+			return
+		}
+		w.Write([]byte(profile.Account))
+	}))
+
+	tests := []struct {
+		name          string
+		authorization string
+		wantStatus    int
+		wantChallenge string
+	}{
+		{"valid", "Bearer valid", http.StatusOK, ""},
+		{"cached", "bearer valid", http.StatusOK, ""},
+		{"missing", "", http.StatusUnauthorized, `Bearer realm="api"`},
+		{"other scheme", "Basic dXNlcjpwYXNz", http.StatusUnauthorized, `Bearer realm="api"`},
+		{"malformed", "Bearer a b", http.StatusBadRequest, `Bearer realm="api", error="invalid_request", error_description="malformed bearer token"`},
+		{"invalid", "Bearer invalid", http.StatusUnauthorized, `Bearer realm="api", error="invalid_token"`},
+		{"insufficient scope", "Bearer basic", http.StatusForbidden, `Bearer realm="api", error="insufficient_scope", error_description="missing scopes essential", scope="essential"`},
+		{"unknown scopes", "Bearer unknown", http.StatusForbidden, `Bearer realm="api", error="insufficient_scope", error_description="scopes of the token are unknown", scope="essential"`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			if tt.authorization != "" {
+				req.Header.Set("Authorization", tt.authorization)
+			}
+
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, req)
+
+			if rec.Code != tt.wantStatus {
+				t.Errorf("Authenticator.Handler() status = %d, want %d", rec.Code, tt.wantStatus)
+			}
+			if got := rec.Header().Get("WWW-Authenticate"); got != tt.wantChallenge {
+				t.Errorf("Authenticator.Handler() WWW-Authenticate = %q, want %q", got, tt.wantChallenge)
+			}
+		})
+	}
+
+	// "valid" is validated once and then served from the cache, "invalid",
+	// "basic" and "unknown" once each.
+	if calls != 4 {
+		t.Errorf("Validator called %d times, want 4", calls)
+	}
+}
+
+func TestIntrospection_Validate(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.PostFormValue("token") == "active" {
+			w.Write([]byte(`{"active":true,"scope":"basic","client_id":"client","sub":"user"}`))
+			return
+		}
+		w.Write([]byte(`{"active":false}`))
+	}))
+	defer ts.Close()
+
+	client := jaccount.NewClient(nil)
+	client.AuthBaseURL, _ = url.Parse(ts.URL + "/oauth2/")
+
+	tests := []struct {
+		name     string
+		token    string
+		audience string
+		wantErr  bool
+	}{
+		{"active", "active", "client", false},
+		{"inactive", "inactive", "", true},
+		{"other audience", "active", "other", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			v := &Introspection{Client: client, Audience: tt.audience}
+			got, err := v.Validate(context.Background(), tt.token)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Introspection.Validate() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !tt.wantErr && (got.TokenInfo.Subject != "user" || !got.Scopes.Contains(jaccount.ScopeBasic)) {
+				t.Errorf("Introspection.Validate() = %+v", got)
+			}
+		})
+	}
+}
+
+func signIDToken(t *testing.T, key []byte, aud string) string {
+	signer, err := jose.NewSigner(jose.SigningKey{Algorithm: jose.HS256, Key: key}, nil)
+	if err != nil {
+		t.Fatalf("jose.NewSigner() error = %v", err)
+	}
+
+	raw, err := jwt.Signed(signer).Claims(map[string]interface{}{
+		"iss":  jaccount.Issuer,
+		"aud":  aud,
+		"sub":  "admin",
+		"type": "faculty",
+		"exp":  time.Now().Add(time.Hour).Unix(),
+	}).CompactSerialize()
+	if err != nil {
+		t.Fatalf("CompactSerialize() error = %v", err)
+	}
+	return raw
+}
+
+func TestIDTokenVerifier_Validate(t *testing.T) {
+	keys := jaccount.StaticKeySet{{Key: []byte("client secret"), Algorithm: "HS256"}}
+
+	tests := []struct {
+		name    string
+		keys    jaccount.KeySet
+		token   string
+		wantErr bool
+	}{
+		{"valid", keys, signIDToken(t, []byte("client secret"), "client"), false},
+		{"forged", keys, signIDToken(t, []byte("attacker secret"), "client"), true},
+		{"other audience", keys, signIDToken(t, []byte("client secret"), "other"), true},
+		{"no keys", nil, signIDToken(t, []byte("client secret"), "client"), true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			v := &IDTokenVerifier{Keys: tt.keys, ClientID: "client"}
+			got, err := v.Validate(context.Background(), tt.token)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("IDTokenVerifier.Validate() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !tt.wantErr && got.IDToken.Subject != "admin" {
+				t.Errorf("IDTokenVerifier.Validate() = %+v", got.IDToken)
+			}
+		})
+	}
+}
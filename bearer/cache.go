@@ -0,0 +1,93 @@
+/*
+Copyright 2021 The Go jAccount Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package bearer
+
+import (
+	"crypto/sha256"
+	"sync"
+	"time"
+)
+
+const defaultCacheSize = 10000
+
+type cacheEntry struct {
+	result  *Result
+	expires time.Time
+}
+
+// cache is a TTL cache of validated tokens. It is keyed by the SHA-256 hash of
+// the token so that the tokens themselves are not kept in memory.
+type cache struct {
+	mu      sync.Mutex
+	entries map[[sha256.Size]byte]cacheEntry
+}
+
+func (c *cache) get(token string) (*Result, bool) {
+	key := sha256.Sum256([]byte(token))
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	entry, ok := c.entries[key]
+	if !ok {
+		return nil, false
+	}
+	if time.Now().After(entry.expires) {
+		delete(c.entries, key)
+		return nil, false
+	}
+	return entry.result, true
+}
+
+func (c *cache) put(token string, result *Result, ttl time.Duration, size int) {
+	expires := time.Now().Add(ttl)
+	if !result.Expiry.IsZero() && result.Expiry.Before(expires) {
+		expires = result.Expiry
+	}
+	if size <= 0 {
+		size = defaultCacheSize
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.entries == nil {
+		c.entries = make(map[[sha256.Size]byte]cacheEntry)
+	}
+
+	if len(c.entries) >= size {
+		c.evict(size)
+	}
+	c.entries[sha256.Sum256([]byte(token))] = cacheEntry{result: result, expires: expires}
+}
+
+// evict removes expired entries, then arbitrary ones until there is room for a
+// new entry.
+func (c *cache) evict(size int) {
+	now := time.Now()
+	for key, entry := range c.entries {
+		if now.After(entry.expires) {
+			delete(c.entries, key)
+		}
+	}
+	for key := range c.entries {
+		if len(c.entries) < size {
+			break
+		}
+		delete(c.entries, key)
+	}
+}
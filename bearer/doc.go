@@ -0,0 +1,30 @@
+/*
+Copyright 2021 The Go jAccount Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+/*
+Package bearer provides net/http middleware for resource servers that accept
+jAccount access tokens or ID tokens as OAuth 2.0 bearer tokens.
+
+Tokens are validated by a Validator, either through token introspection, a
+call to the profile API with the token, or ID token signature verification.
+Results are cached by the hash of the token, and the identity of the user is
+stored in the request context, where it can be read with
+jaccount.TokenInfoFromContext, jaccount.ProfileFromContext and
+jaccount.IDTokenFromContext.
+
+Errors are reported with the WWW-Authenticate header as specified in RFC 6750.
+*/
+package bearer
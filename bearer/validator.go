@@ -0,0 +1,145 @@
+/*
+Copyright 2021 The Go jAccount Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package bearer
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"github.com/dyweb/go-jaccount/jaccount"
+	"golang.org/x/oauth2"
+)
+
+// Validator validates a bearer token and returns the identity it belongs to.
+// A token that is invalid, expired or revoked is reported with an *Error with
+// the invalid_token code; any other error means the token could not be
+// validated at all.
+type Validator interface {
+	Validate(ctx context.Context, token string) (*Result, error)
+}
+
+// ValidatorFunc is an adapter to allow the use of ordinary functions as
+// validators.
+type ValidatorFunc func(ctx context.Context, token string) (*Result, error)
+
+// Validate calls f(ctx, token).
+func (f ValidatorFunc) Validate(ctx context.Context, token string) (*Result, error) {
+	return f(ctx, token)
+}
+
+// Introspection validates access tokens with the token introspection endpoint.
+type Introspection struct {
+	// Client is the client of the resource server. Its HTTP client either
+	// carries a token with the introspect scope, or Options carries the
+	// client credentials.
+	Client *jaccount.Client
+
+	Options *jaccount.TokenOptions
+
+	// Audience, if set, must match the client ID the token was issued to.
+	Audience string
+}
+
+// Validate implements Validator.
+func (v *Introspection) Validate(ctx context.Context, token string) (*Result, error) {
+	opts := &jaccount.TokenOptions{}
+	if v.Options != nil {
+		*opts = *v.Options
+	}
+	opts.TokenTypeHint = jaccount.AccessTokenHint
+
+	info, err := v.Client.Token.Introspect(ctx, token, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	if !info.Active {
+		return nil, &Error{Code: ErrorInvalidToken, Description: "token is not active"}
+	}
+	if v.Audience != "" && info.ClientID != v.Audience {
+		return nil, &Error{Code: ErrorInvalidToken, Description: "token issued to a different client"}
+	}
+
+	return &Result{
+		TokenInfo: info,
+		Scopes:    jaccount.NewScopes(info.Scopes()...),
+		Expiry:    info.ExpiresAt(),
+	}, nil
+}
+
+// ProfileLookup validates access tokens by fetching the profile of the user
+// with them. It needs no credentials of its own, but a token without the
+// basic scope is rejected.
+type ProfileLookup struct {
+	// NewClient returns a client that authenticates with token. Defaults to
+	// a client for the jAccount API.
+	NewClient func(ctx context.Context, token string) *jaccount.Client
+}
+
+// Validate implements Validator.
+func (v *ProfileLookup) Validate(ctx context.Context, token string) (*Result, error) {
+	newClient := v.NewClient
+	if newClient == nil {
+		newClient = defaultClient
+	}
+
+	profile, err := newClient(ctx, token).Profile.Get(ctx)
+	if err != nil {
+		var errResp *jaccount.ErrorResponse
+		if errors.As(err, &errResp) && (errResp.Response.StatusCode == http.StatusUnauthorized || errResp.Response.StatusCode == http.StatusForbidden) {
+			return nil, &Error{Code: ErrorInvalidToken, Description: errResp.InternalError}
+		}
+		return nil, err
+	}
+
+	return &Result{Profile: profile}, nil
+}
+
+func defaultClient(ctx context.Context, token string) *jaccount.Client {
+	return jaccount.NewClient(oauth2.NewClient(ctx, oauth2.StaticTokenSource(&oauth2.Token{AccessToken: token})))
+}
+
+// IDTokenVerifier validates jAccount ID tokens. The signature of the token is
+// verified with Keys before any of its claims are trusted.
+type IDTokenVerifier struct {
+	// Keys are the keys ID tokens are signed with, for example a
+	// jaccount.RemoteKeySet, or a jaccount.StaticKeySet holding the client
+	// secret for HS256. Every token is rejected if it is nil.
+	Keys jaccount.KeySet
+
+	// ClientID, if set, must be the audience of the ID token.
+	ClientID string
+}
+
+// Validate implements Validator.
+func (v *IDTokenVerifier) Validate(ctx context.Context, token string) (*Result, error) {
+	if v.Keys == nil {
+		return nil, errors.New("bearer: IDTokenVerifier has no keys")
+	}
+
+	idToken, err := jaccount.VerifySignedToken(ctx, token, v.Keys)
+	if err != nil {
+		return nil, &Error{Code: ErrorInvalidToken, Description: err.Error()}
+	}
+
+	if v.ClientID != "" && idToken.Audience != v.ClientID {
+		return nil, &Error{Code: ErrorInvalidToken, Description: "ID token issued to a different client"}
+	}
+
+	return &Result{IDToken: idToken, Expiry: idToken.Expiry}, nil
+}
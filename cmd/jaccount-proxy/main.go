@@ -0,0 +1,176 @@
+/*
+Copyright 2021 The Go jAccount Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Command jaccount-proxy is a reverse proxy that puts jAccount login in front
+// of an upstream HTTP service. With -forward-auth, it instead serves the
+// forward authentication endpoints /auth and /start for another reverse proxy,
+// such as nginx, Traefik or Caddy.
+//
+// The client ID and secret are read from the CLIENT_ID and CLIENT_SECRET
+// environment variables, and the keys from COOKIE_SECRET and HEADER_SECRET,
+// each a base64-encoded 32-byte key.
+//
+// Sessions are kept in memory unless COOKIE_SECRET is set. Cookie sessions do
+// not hold the profile of the user, which would not fit in a cookie, so they
+// cannot be used with -allow-orgs and the organization header is left empty.
+package main
+
+import (
+	"encoding/base64"
+	"flag"
+	"log"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+
+	"github.com/dyweb/go-jaccount/forwardauth"
+	"github.com/dyweb/go-jaccount/jaccount"
+	"github.com/dyweb/go-jaccount/proxy"
+	"github.com/dyweb/go-jaccount/session"
+	"golang.org/x/oauth2"
+)
+
+var (
+	ClientID     = os.Getenv("CLIENT_ID")
+	ClientSecret = os.Getenv("CLIENT_SECRET")
+	CookieSecret = os.Getenv("COOKIE_SECRET")
+	HeaderSecret = os.Getenv("HEADER_SECRET")
+)
+
+func main() {
+	var (
+		listen       = flag.String("listen", ":4180", "address to listen on")
+		upstream     = flag.String("upstream", "", "URL of the upstream service")
+		externalURL  = flag.String("external-url", "http://localhost:4180", "URL the proxy is reached at")
+		allowTypes   = flag.String("allow-types", "", "comma-separated user types to allow, such as student,faculty")
+		allowAccount = flag.String("allow-accounts", "", "comma-separated jAccount accounts to allow")
+		allowOrgs    = flag.String("allow-orgs", "", "comma-separated organization IDs to allow")
+		forwardAuth  = flag.Bool("forward-auth", false, "serve forward authentication endpoints instead of proxying")
+		startURL     = flag.String("start-url", "", "URL of the start endpoint to redirect unauthenticated users to in forward authentication mode")
+		allowedHosts = flag.String("allowed-hosts", "", "comma-separated hosts users may return to after login, a leading dot matches subdomains")
+		cookieDomain = flag.String("cookie-domain", "", "domain of the session cookie")
+	)
+	flag.Parse()
+
+	if ClientID == "" || ClientSecret == "" {
+		log.Fatal("CLIENT_ID and CLIENT_SECRET must be set")
+	}
+
+	cookie := session.CookieOptions{Domain: *cookieDomain}
+
+	var store session.Store
+	if CookieSecret != "" {
+		if *allowOrgs != "" {
+			log.Fatal("-allow-orgs needs the profile of the user, which is not kept in cookie sessions; unset COOKIE_SECRET")
+		}
+		cookieStore, err := session.NewCookieStore(decodeKey("COOKIE_SECRET", CookieSecret))
+		if err != nil {
+			log.Fatalf("invalid COOKIE_SECRET: %s", err)
+		}
+		cookieStore.Cookie = cookie
+		store = cookieStore
+	} else {
+		log.Println("COOKIE_SECRET not set, keeping sessions in memory")
+		store = &session.ServerStore{Backend: &session.MemoryBackend{}, Cookie: cookie}
+	}
+
+	var signingKey []byte
+	if HeaderSecret != "" {
+		signingKey = decodeKey("HEADER_SECRET", HeaderSecret)
+	} else {
+		log.Println("HEADER_SECRET not set, identity headers are not signed")
+	}
+
+	base := strings.TrimSuffix(*externalURL, "/")
+	manager := &session.Manager{
+		Config: &oauth2.Config{
+			ClientID:     ClientID,
+			ClientSecret: ClientSecret,
+			Endpoint:     jaccount.Endpoint,
+			RedirectURL:  base + proxy.DefaultPrefix + "/callback",
+			Scopes:       []string{jaccount.ScopeOpenID, jaccount.ScopeEssential},
+		},
+		Store:        store,
+		LoginURL:     base + proxy.DefaultPrefix + "/login",
+		AllowedHosts: split(*allowedHosts),
+		FetchProfile: CookieSecret == "",
+	}
+	allow := proxy.AllowList(types(*allowTypes), split(*allowAccount), split(*allowOrgs))
+
+	if *forwardAuth {
+		h := &forwardauth.Handler{
+			Session:    manager,
+			Policy:     allow,
+			SigningKey: signingKey,
+			StartURL:   *startURL,
+		}
+
+		mux := http.NewServeMux()
+		mux.Handle(forwardauth.DefaultAuthPath, h.AuthHandler())
+		mux.Handle(forwardauth.DefaultStartPath, h.StartHandler())
+		mux.Handle(proxy.DefaultPrefix+"/login", manager.LoginHandler())
+		mux.Handle(proxy.DefaultPrefix+"/callback", manager.CallbackHandler())
+		mux.Handle(proxy.DefaultPrefix+"/logout", manager.LogoutHandler())
+		mux.HandleFunc(proxy.DefaultHealthPath, func(w http.ResponseWriter, r *http.Request) {
+			w.Write([]byte("OK"))
+		})
+
+		log.Printf("listening on %s, serving forward authentication", *listen)
+		log.Fatal(http.ListenAndServe(*listen, mux))
+	}
+
+	upstreamURL, err := url.Parse(*upstream)
+	if err != nil || upstreamURL.Scheme == "" || upstreamURL.Host == "" {
+		log.Fatalf("invalid upstream URL %q", *upstream)
+	}
+
+	p := &proxy.Proxy{
+		Upstream:   upstreamURL,
+		Session:    manager,
+		Policy:     allow,
+		SigningKey: signingKey,
+	}
+
+	log.Printf("listening on %s, proxying to %s", *listen, upstreamURL)
+	log.Fatal(http.ListenAndServe(*listen, p))
+}
+
+func decodeKey(name, value string) []byte {
+	key, err := base64.StdEncoding.DecodeString(value)
+	if err != nil || len(key) != 32 {
+		log.Fatalf("%s must be a base64-encoded 32-byte key", name)
+	}
+	return key
+}
+
+func split(value string) []string {
+	var values []string
+	for _, v := range strings.Split(value, ",") {
+		if v = strings.TrimSpace(v); v != "" {
+			values = append(values, v)
+		}
+	}
+	return values
+}
+
+func types(value string) []jaccount.Type {
+	var types []jaccount.Type
+	for _, v := range split(value) {
+		types = append(types, jaccount.Type(v))
+	}
+	return types
+}
@@ -0,0 +1,111 @@
+/*
+Copyright 2021 The Go jAccount Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"github.com/dyweb/go-jaccount/academic"
+	"github.com/dyweb/go-jaccount/ical"
+	"github.com/dyweb/go-jaccount/jaccount"
+)
+
+func runCalendar(ctx context.Context, args []string) error {
+	fs := flag.NewFlagSet("calendar", flag.ExitOnError)
+	var (
+		term   = fs.String("term", "", "term to export, such as 2026-2027-1")
+		start  = fs.String("start", "", "first day of the term, such as 2026-09-14, for terms without a known calendar")
+		file   = fs.String("calendar", "", "load term calendars from this JSON or YAML file")
+		remind = fs.Duration("remind", 0, "add a reminder this long before every class")
+		exams  = fs.Bool("exams", false, "include the exams of the term")
+		output = fs.String("o", "", "write the calendar to this file instead of standard output")
+	)
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), "usage: jaccount calendar -term TERM [flags]")
+		fs.PrintDefaults()
+	}
+	fs.Parse(args)
+
+	t, err := jaccount.ParseTerm(*term)
+	if err != nil {
+		return err
+	}
+	calendar := academic.Default
+	if *file != "" {
+		terms, err := academic.LoadFile(*file)
+		if err != nil {
+			return err
+		}
+		calendar = &academic.Calendar{Terms: terms, Bells: academic.SJTUBells}
+	}
+
+	termCalendar, ok := calendar.Term(t)
+	if *start != "" {
+		d, err := academic.ParseDate(*start)
+		if err != nil {
+			return err
+		}
+		termCalendar = &academic.TermCalendar{Term: t, Start: d, Weeks: 16}
+	} else if !ok {
+		return fmt.Errorf("no calendar for term %s, set -start", t)
+	}
+
+	client, err := newClient(ctx)
+	if err != nil {
+		return err
+	}
+	lessons, err := client.Lessons.List(ctx, t)
+	if err != nil {
+		return err
+	}
+
+	s := &ical.Schedule{Term: termCalendar, Bells: calendar.Bells}
+	if *remind > 0 {
+		s.Reminders = []time.Duration{*remind}
+	}
+	events, err := s.LessonEvents(lessons)
+	if err != nil {
+		return err
+	}
+
+	if *exams {
+		list, err := client.Exams.List(ctx, t)
+		if err != nil {
+			return err
+		}
+		events = append(events, s.ExamEvents(list)...)
+	}
+
+	var w io.Writer = os.Stdout
+	if *output != "" {
+		f, err := os.Create(*output)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		w = f
+	}
+
+	c := &ical.Calendar{Name: t.String(), Events: events}
+	_, err = c.WriteTo(w)
+	return err
+}
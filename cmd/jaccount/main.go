@@ -0,0 +1,98 @@
+/*
+Copyright 2021 The Go jAccount Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Command jaccount accesses the jAccount API from the command line.
+//
+// Usage:
+//
+//	jaccount <command> [flags] [arguments]
+//
+// The commands are:
+//
+//	calendar  export the course schedule to iCalendar
+//	sync      synchronize a local directory with jBox
+//
+// The access token is read from the ACCESS_TOKEN environment variable.
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"os/signal"
+	"sort"
+
+	"github.com/dyweb/go-jaccount/jaccount"
+	"golang.org/x/oauth2"
+)
+
+var AccessToken = os.Getenv("ACCESS_TOKEN")
+
+// commands maps command names to their implementations, which receive the
+// arguments following the command name.
+var commands = map[string]struct {
+	run   func(ctx context.Context, args []string) error
+	short string
+}{
+	"calendar": {runCalendar, "export the course schedule to iCalendar"},
+	"sync":     {runSync, "synchronize a local directory with jBox"},
+}
+
+func main() {
+	log.SetFlags(0)
+	log.SetPrefix("jaccount: ")
+
+	if len(os.Args) < 2 {
+		usage()
+	}
+	cmd, ok := commands[os.Args[1]]
+	if !ok {
+		usage()
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	if err := cmd.run(ctx, os.Args[2:]); err != nil {
+		log.Fatal(err)
+	}
+}
+
+func usage() {
+	fmt.Fprintln(os.Stderr, "usage: jaccount <command> [flags] [arguments]")
+	fmt.Fprintln(os.Stderr)
+	fmt.Fprintln(os.Stderr, "The commands are:")
+	names := make([]string, 0, len(commands))
+	for name := range commands {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		fmt.Fprintf(os.Stderr, "\t%-10s%s\n", name, commands[name].short)
+	}
+	os.Exit(2)
+}
+
+// newClient returns a client authenticated with ACCESS_TOKEN.
+func newClient(ctx context.Context) (*jaccount.Client, error) {
+	if AccessToken == "" {
+		return nil, fmt.Errorf("ACCESS_TOKEN must be set")
+	}
+
+	ts := oauth2.StaticTokenSource(&oauth2.Token{AccessToken: AccessToken})
+	return jaccount.NewClient(oauth2.NewClient(ctx, ts)), nil
+}
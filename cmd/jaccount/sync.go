@@ -0,0 +1,123 @@
+/*
+Copyright 2021 The Go jAccount Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/dyweb/go-jaccount/filesync"
+)
+
+func runSync(ctx context.Context, args []string) error {
+	fs := flag.NewFlagSet("sync", flag.ExitOnError)
+	var (
+		direction   = fs.String("direction", "push", "push local changes, pull remote changes, or sync both ways")
+		deletes     = fs.String("delete", "none", "deletion policy: none, propagate or mirror")
+		include     = fs.String("include", "", "comma-separated globs of files to sync")
+		exclude     = fs.String("exclude", "", "comma-separated globs of files and directories to skip")
+		concurrency = fs.Int("concurrency", filesync.DefaultConcurrency, "number of concurrent transfers")
+		stateFile   = fs.String("state", "", "state file kept between runs (default LOCAL_DIR/.jaccount-sync.json)")
+		dryRun      = fs.Bool("dry-run", false, "print the actions without performing them")
+	)
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), "usage: jaccount sync [flags] LOCAL_DIR REMOTE_DIR")
+		fs.PrintDefaults()
+	}
+	fs.Parse(args)
+	if fs.NArg() != 2 {
+		fs.Usage()
+		os.Exit(2)
+	}
+
+	s := &filesync.Syncer{
+		LocalDir:    fs.Arg(0),
+		RemoteDir:   fs.Arg(1),
+		Include:     split(*include),
+		Exclude:     split(*exclude),
+		Concurrency: *concurrency,
+		StateFile:   *stateFile,
+		DryRun:      *dryRun,
+	}
+	if s.StateFile == "" {
+		s.StateFile = filepath.Join(s.LocalDir, ".jaccount-sync.json")
+	}
+
+	switch *direction {
+	case "push":
+		s.Direction = filesync.Push
+	case "pull":
+		s.Direction = filesync.Pull
+	case "both":
+		s.Direction = filesync.TwoWay
+	default:
+		return fmt.Errorf("invalid direction %q", *direction)
+	}
+
+	switch *deletes {
+	case "none":
+		s.Delete = filesync.DeleteNone
+	case "propagate":
+		s.Delete = filesync.DeletePropagate
+	case "mirror":
+		s.Delete = filesync.DeleteMirror
+	default:
+		return fmt.Errorf("invalid deletion policy %q", *deletes)
+	}
+
+	client, err := newClient(ctx)
+	if err != nil {
+		return err
+	}
+	s.Storage = client.Storage
+
+	s.Log = func(a filesync.Action, err error) {
+		if err != nil {
+			log.Printf("%s: %s", a, err)
+		} else {
+			log.Print(a)
+		}
+	}
+
+	result, err := s.Run(ctx)
+	if result != nil {
+		if *dryRun {
+			for _, a := range result.Actions {
+				fmt.Println(a)
+			}
+		}
+		for _, a := range result.Conflicts {
+			log.Print(a)
+		}
+	}
+	return err
+}
+
+func split(value string) []string {
+	var values []string
+	for _, v := range strings.Split(value, ",") {
+		if v = strings.TrimSpace(v); v != "" {
+			values = append(values, v)
+		}
+	}
+	return values
+}
@@ -0,0 +1,32 @@
+/*
+Copyright 2021 The Go jAccount Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+/*
+Package filesync synchronizes a local directory with a folder in jBox, the
+campus cloud storage, through the storage API of jAccount.
+
+A Syncer plans the transfers that bring the two sides in line, comparing size,
+modification time and SHA-256 checksum, and then performs them concurrently. It
+syncs one way, pushing local changes or pulling remote ones, or both ways. A
+state file records the files as of the last run, so that unchanged files are
+recognized without hashing them and deletions can be told apart from new files
+on the other side.
+
+Files removed on one side are only removed on the other if the DeletePolicy
+says so. In a two-way sync, a file changed on both sides since the last run is
+reported as a conflict and left alone.
+*/
+package filesync
@@ -0,0 +1,85 @@
+/*
+Copyright 2021 The Go jAccount Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package filesync
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// State records the files as they were on both sides after the last run.
+type State struct {
+	Files map[string]*FileState `json:"files"`
+}
+
+// FileState records a file that was in sync, by its slash-separated path
+// relative to the synchronized directories.
+type FileState struct {
+	Size          int64     `json:"size"`
+	LocalModTime  time.Time `json:"localModTime"`
+	RemoteModTime time.Time `json:"remoteModTime"`
+	SHA256        string    `json:"sha256"`
+}
+
+// LoadState reads the state file. A missing file yields an empty State.
+func LoadState(name string) (*State, error) {
+	state := &State{Files: make(map[string]*FileState)}
+
+	data, err := ioutil.ReadFile(name)
+	if os.IsNotExist(err) {
+		return state, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if err := json.Unmarshal(data, state); err != nil {
+		return nil, err
+	}
+	if state.Files == nil {
+		state.Files = make(map[string]*FileState)
+	}
+
+	return state, nil
+}
+
+// Save writes the state file atomically.
+func (s *State) Save(name string) error {
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	f, err := ioutil.TempFile(filepath.Dir(name), filepath.Base(name)+".*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(f.Name())
+
+	_, err = f.Write(data)
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		return err
+	}
+
+	return os.Rename(f.Name(), name)
+}
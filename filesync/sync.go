@@ -0,0 +1,615 @@
+/*
+Copyright 2021 The Go jAccount Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package filesync
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"sync"
+
+	"github.com/dyweb/go-jaccount/jaccount"
+)
+
+// Direction is the direction in which changes are synced.
+type Direction int
+
+const (
+	// Push copies local changes to the remote directory.
+	Push Direction = iota
+	// Pull copies remote changes to the local directory.
+	Pull
+	// TwoWay copies changes on either side to the other.
+	TwoWay
+)
+
+// DeletePolicy decides what happens to a file that exists on only one side.
+type DeletePolicy int
+
+const (
+	// DeleteNone never deletes files. A file removed on one side is copied
+	// back from the other.
+	DeleteNone DeletePolicy = iota
+	// DeletePropagate deletes a file on one side if it was removed on the
+	// other since the last run. It relies on the state file.
+	DeletePropagate
+	// DeleteMirror deletes every file on the destination that is missing
+	// from the source, making it an exact mirror. It is not allowed in a
+	// two-way sync.
+	DeleteMirror
+)
+
+// ErrMissingDir is returned when the local or remote directory does not exist
+// and the DeletePolicy would delete the files on the other side because of it.
+// With DeleteNone, or in the direction of the missing side, it is created.
+var ErrMissingDir = errors.New("directory does not exist")
+
+// Op is an operation performed by a sync.
+type Op string
+
+const (
+	OpUpload       Op = "upload"
+	OpDownload     Op = "download"
+	OpDeleteLocal  Op = "delete-local"
+	OpDeleteRemote Op = "delete-remote"
+
+	// OpConflict reports a file changed on both sides, which is not synced.
+	OpConflict Op = "conflict"
+
+	// opRecord records a file found to be in sync in the state.
+	opRecord Op = "record"
+)
+
+// Action is an operation on a file, by its slash-separated path relative to
+// the synchronized directories.
+type Action struct {
+	Op     Op
+	Path   string
+	Reason string
+
+	local  *file
+	remote *file
+}
+
+func (a Action) String() string {
+	return fmt.Sprintf("%s %s (%s)", a.Op, a.Path, a.Reason)
+}
+
+// ActionError is an action that failed.
+type ActionError struct {
+	Action Action
+	Err    error
+}
+
+func (e *ActionError) Error() string {
+	return fmt.Sprintf("%s %s: %v", e.Action.Op, e.Action.Path, e.Err)
+}
+
+func (e *ActionError) Unwrap() error {
+	return e.Err
+}
+
+// Result is the outcome of a run.
+type Result struct {
+	// Actions are the actions performed, or planned in a dry run.
+	Actions []Action
+	// Conflicts are the files changed on both sides.
+	Conflicts []Action
+	// Failed are the actions that failed.
+	Failed []*ActionError
+}
+
+// DefaultConcurrency is the number of concurrent transfers unless specified.
+const DefaultConcurrency = 4
+
+// Syncer synchronizes a local directory with a remote storage directory.
+type Syncer struct {
+	Storage   *jaccount.StorageService
+	LocalDir  string
+	RemoteDir string
+
+	Direction Direction
+	Delete    DeletePolicy
+
+	// Include, if not empty, limits the sync to the files matching one of
+	// the globs. Files matching one of Exclude are skipped, and so are
+	// directories with everything below them. A glob with a slash matches
+	// the relative path, one without matches the base name.
+	Include []string
+	Exclude []string
+
+	// Concurrency defaults to DefaultConcurrency.
+	Concurrency int
+
+	// ChunkThreshold is the size above which files are uploaded in
+	// resumable chunks. It defaults to jaccount.DefaultChunkSize.
+	ChunkThreshold int64
+
+	// StateFile, if set, is where the state is kept between runs. Without
+	// it, every run is a first run: all files present on both sides are
+	// hashed and DeletePropagate deletes nothing.
+	StateFile string
+
+	// DryRun plans the actions without performing them.
+	DryRun bool
+
+	// Log, if set, is called after every action performed.
+	Log func(Action, error)
+
+	mu       sync.Mutex
+	state    *State
+	madeDirs map[string]bool
+}
+
+// Plan returns the actions that a run would perform, and the conflicts.
+func (s *Syncer) Plan(ctx context.Context) ([]Action, []Action, error) {
+	if err := s.load(); err != nil {
+		return nil, nil, err
+	}
+
+	actions, err := s.plan(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var planned, conflicts []Action
+	for _, a := range actions {
+		switch a.Op {
+		case opRecord:
+		case OpConflict:
+			conflicts = append(conflicts, a)
+		default:
+			planned = append(planned, a)
+		}
+	}
+	return planned, conflicts, nil
+}
+
+// Run performs a sync. The state file is updated with the actions that
+// succeeded even if others failed, in which case the error summarizes the
+// failures in the Result.
+func (s *Syncer) Run(ctx context.Context) (*Result, error) {
+	if err := s.load(); err != nil {
+		return nil, err
+	}
+
+	actions, err := s.plan(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &Result{}
+	var todo []Action
+	for _, a := range actions {
+		switch a.Op {
+		case OpConflict:
+			result.Conflicts = append(result.Conflicts, a)
+		case opRecord:
+			if !s.DryRun {
+				s.record(a.Path, a.local, a.remote)
+			}
+		default:
+			todo = append(todo, a)
+		}
+	}
+
+	if s.DryRun {
+		result.Actions = todo
+		return result, nil
+	}
+
+	s.perform(ctx, todo, result)
+	sort.Slice(result.Actions, func(i, j int) bool {
+		return result.Actions[i].Path < result.Actions[j].Path
+	})
+	sort.Slice(result.Failed, func(i, j int) bool {
+		return result.Failed[i].Action.Path < result.Failed[j].Action.Path
+	})
+
+	if s.StateFile != "" {
+		if err := s.state.Save(s.StateFile); err != nil {
+			return result, err
+		}
+	}
+
+	if len(result.Failed) > 0 {
+		return result, fmt.Errorf("%d of %d actions failed, first: %w", len(result.Failed), len(todo), result.Failed[0])
+	}
+	return result, nil
+}
+
+func (s *Syncer) load() error {
+	if s.Direction == TwoWay && s.Delete == DeleteMirror {
+		return errors.New("DeleteMirror is not allowed in a two-way sync")
+	}
+
+	s.madeDirs = make(map[string]bool)
+	if s.StateFile == "" {
+		s.state = &State{Files: make(map[string]*FileState)}
+		return nil
+	}
+
+	state, err := LoadState(s.StateFile)
+	if err != nil {
+		return err
+	}
+	s.state = state
+	return nil
+}
+
+// plan compares both sides and the state, in order of path.
+func (s *Syncer) plan(ctx context.Context) ([]Action, error) {
+	local, localExists, err := s.scanLocal()
+	if err != nil {
+		return nil, err
+	}
+	remote, remoteExists, err := s.scanRemote(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	// A missing directory is an empty one to copy into, but must not make the
+	// other side look deleted, such as when a mount or path is wrong.
+	if !localExists && s.deletesRemote() {
+		return nil, fmt.Errorf("%w: local directory %s", ErrMissingDir, s.LocalDir)
+	}
+	if !remoteExists && s.deletesLocal() {
+		return nil, fmt.Errorf("%w: remote directory %s", ErrMissingDir, s.RemoteDir)
+	}
+
+	paths := make(map[string]bool)
+	for rel := range local {
+		paths[rel] = true
+	}
+	for rel := range remote {
+		paths[rel] = true
+	}
+	for rel := range s.state.Files {
+		paths[rel] = true
+	}
+	sorted := make([]string, 0, len(paths))
+	for rel := range paths {
+		sorted = append(sorted, rel)
+	}
+	sort.Strings(sorted)
+
+	var actions []Action
+	for _, rel := range sorted {
+		a, err := s.decide(rel, local[rel], remote[rel], s.state.Files[rel])
+		if err != nil {
+			return nil, err
+		}
+		if a != nil {
+			actions = append(actions, *a)
+		}
+	}
+	return actions, nil
+}
+
+// deletesRemote reports whether the sync may delete remote files that are
+// missing locally. Deletions are only propagated for files in the state.
+func (s *Syncer) deletesRemote() bool {
+	return s.Direction == Push && s.Delete == DeleteMirror ||
+		s.Direction != Pull && s.Delete == DeletePropagate && len(s.state.Files) > 0
+}
+
+// deletesLocal reports whether the sync may delete local files that are
+// missing remotely.
+func (s *Syncer) deletesLocal() bool {
+	return s.Direction == Pull && s.Delete == DeleteMirror ||
+		s.Direction != Push && s.Delete == DeletePropagate && len(s.state.Files) > 0
+}
+
+// decide returns the action for a file, or nil if there is nothing to do.
+func (s *Syncer) decide(rel string, l *file, r *file, prev *FileState) (*Action, error) {
+	action := func(op Op, reason string) (*Action, error) {
+		return &Action{Op: op, Path: rel, Reason: reason, local: l, remote: r}, nil
+	}
+
+	if l == nil && r == nil {
+		// Gone from both sides.
+		s.forget(rel)
+		return nil, nil
+	}
+
+	if l != nil && r != nil {
+		localChanged := prev == nil || l.Size != prev.Size || !l.ModTime.Equal(prev.LocalModTime)
+		remoteChanged := prev == nil || r.Size != prev.Size || !r.ModTime.Equal(prev.RemoteModTime) ||
+			r.SHA256 != "" && r.SHA256 != prev.SHA256
+		if !localChanged && !remoteChanged {
+			return nil, nil
+		}
+
+		same, err := s.sameContent(rel, l, r, prev)
+		if err != nil {
+			return nil, err
+		}
+		if same {
+			return action(opRecord, "identical")
+		}
+
+		switch s.Direction {
+		case Push:
+			return action(OpUpload, "modified")
+		case Pull:
+			return action(OpDownload, "modified")
+		}
+		switch {
+		case localChanged && remoteChanged:
+			return action(OpConflict, "modified on both sides")
+		case localChanged:
+			return action(OpUpload, "modified locally")
+		default:
+			return action(OpDownload, "modified remotely")
+		}
+	}
+
+	// The file exists on one side only. It was deleted from the other side
+	// if it was in sync before.
+	deleted := prev != nil
+	if l != nil {
+		switch {
+		case s.Direction == Pull && s.Delete == DeleteMirror:
+			return action(OpDeleteLocal, "not in remote")
+		case s.Direction != Push && s.Delete == DeletePropagate && deleted:
+			if l.Size != prev.Size || !l.ModTime.Equal(prev.LocalModTime) {
+				return action(OpConflict, "deleted remotely, modified locally")
+			}
+			return action(OpDeleteLocal, "deleted remotely")
+		case s.Direction == Pull:
+			return nil, nil
+		case deleted:
+			return action(OpUpload, "deleted remotely")
+		default:
+			return action(OpUpload, "new")
+		}
+	}
+
+	switch {
+	case s.Direction == Push && s.Delete == DeleteMirror:
+		return action(OpDeleteRemote, "not in local")
+	case s.Direction != Pull && s.Delete == DeletePropagate && deleted:
+		if r.Size != prev.Size || !r.ModTime.Equal(prev.RemoteModTime) {
+			return action(OpConflict, "deleted locally, modified remotely")
+		}
+		return action(OpDeleteRemote, "deleted locally")
+	case s.Direction == Push:
+		return nil, nil
+	case deleted:
+		return action(OpDownload, "deleted locally")
+	default:
+		return action(OpDownload, "new")
+	}
+}
+
+// sameContent reports whether the files on both sides have the same content.
+func (s *Syncer) sameContent(rel string, l *file, r *file, prev *FileState) (bool, error) {
+	if l.Size != r.Size {
+		return false, nil
+	}
+	if r.SHA256 == "" {
+		// Without a checksum, the files are only assumed to be the same
+		// if neither was modified after the other.
+		return l.ModTime.Equal(r.ModTime), nil
+	}
+
+	sum, err := s.localHash(rel, l, prev)
+	if err != nil {
+		return false, err
+	}
+	return sum == r.SHA256, nil
+}
+
+// perform runs the actions concurrently.
+func (s *Syncer) perform(ctx context.Context, actions []Action, result *Result) {
+	concurrency := s.Concurrency
+	if concurrency <= 0 {
+		concurrency = DefaultConcurrency
+	}
+
+	var (
+		wg  sync.WaitGroup
+		mu  sync.Mutex
+		sem = make(chan struct{}, concurrency)
+	)
+	for _, a := range actions {
+		a := a
+		sem <- struct{}{}
+		wg.Add(1)
+		go func() {
+			defer func() {
+				<-sem
+				wg.Done()
+			}()
+
+			err := ctx.Err()
+			if err == nil {
+				err = s.do(ctx, a)
+			}
+			if s.Log != nil {
+				s.Log(a, err)
+			}
+
+			mu.Lock()
+			defer mu.Unlock()
+			if err != nil {
+				result.Failed = append(result.Failed, &ActionError{Action: a, Err: err})
+			} else {
+				result.Actions = append(result.Actions, a)
+			}
+		}()
+	}
+	wg.Wait()
+}
+
+func (s *Syncer) do(ctx context.Context, a Action) error {
+	switch a.Op {
+	case OpUpload:
+		return s.upload(ctx, a.Path)
+	case OpDownload:
+		return s.download(ctx, a.Path, a.remote)
+	case OpDeleteLocal:
+		if err := os.Remove(s.localPath(a.Path)); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+		s.forget(a.Path)
+		return nil
+	case OpDeleteRemote:
+		if err := s.Storage.Delete(ctx, s.remotePath(a.Path)); err != nil {
+			return err
+		}
+		s.forget(a.Path)
+		return nil
+	}
+	return fmt.Errorf("unknown operation %q", a.Op)
+}
+
+func (s *Syncer) upload(ctx context.Context, rel string) error {
+	f, err := os.Open(s.localPath(rel))
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	stat, err := f.Stat()
+	if err != nil {
+		return err
+	}
+
+	if err := s.mkdirRemote(ctx, path.Dir(rel)); err != nil {
+		return err
+	}
+
+	threshold := s.ChunkThreshold
+	if threshold <= 0 {
+		threshold = jaccount.DefaultChunkSize
+	}
+
+	var info *jaccount.FileInfo
+	if stat.Size() > threshold {
+		info, err = s.Storage.UploadChunked(ctx, s.remotePath(rel), f, stat.Size(), &jaccount.UploadOptions{Overwrite: true})
+	} else {
+		info, err = s.Storage.Upload(ctx, s.remotePath(rel), f, stat.Size(), true)
+	}
+	if err != nil {
+		return err
+	}
+
+	s.record(rel,
+		&file{Size: stat.Size(), ModTime: stat.ModTime()},
+		&file{Size: info.Size, ModTime: info.ModTime.Time, SHA256: info.SHA256})
+	return nil
+}
+
+// mkdirRemote creates the remote parent directory of uploads once per run.
+func (s *Syncer) mkdirRemote(ctx context.Context, rel string) error {
+	s.mu.Lock()
+	made := s.madeDirs[rel]
+	s.mu.Unlock()
+	if made {
+		return nil
+	}
+
+	if _, err := s.Storage.Mkdir(ctx, s.remotePath(rel)); err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	s.madeDirs[rel] = true
+	s.mu.Unlock()
+	return nil
+}
+
+// download writes the remote file to a temporary file next to the local one,
+// verifies its checksum and renames it into place.
+func (s *Syncer) download(ctx context.Context, rel string, r *file) error {
+	name := s.localPath(rel)
+	if err := os.MkdirAll(filepath.Dir(name), 0755); err != nil {
+		return err
+	}
+
+	body, err := s.Storage.Download(ctx, s.remotePath(rel))
+	if err != nil {
+		return err
+	}
+	defer body.Close()
+
+	tmp, err := ioutil.TempFile(filepath.Dir(name), tempPrefix+"*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	h := sha256.New()
+	_, err = io.Copy(io.MultiWriter(tmp, h), body)
+	if cerr := tmp.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		return err
+	}
+
+	sum := hex.EncodeToString(h.Sum(nil))
+	if r.SHA256 != "" && sum != r.SHA256 {
+		return &jaccount.ChecksumError{Path: s.remotePath(rel), Expected: r.SHA256, Actual: sum}
+	}
+
+	if err := os.Chtimes(tmp.Name(), r.ModTime, r.ModTime); err != nil {
+		return err
+	}
+	if err := os.Rename(tmp.Name(), name); err != nil {
+		return err
+	}
+
+	stat, err := os.Stat(name)
+	if err != nil {
+		return err
+	}
+	s.record(rel, &file{Size: stat.Size(), ModTime: stat.ModTime(), SHA256: sum}, r)
+	return nil
+}
+
+func (s *Syncer) record(rel string, l *file, r *file) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	sum := r.SHA256
+	if sum == "" {
+		sum = l.SHA256
+	}
+	s.state.Files[rel] = &FileState{
+		Size:          l.Size,
+		LocalModTime:  l.ModTime,
+		RemoteModTime: r.ModTime,
+		SHA256:        sum,
+	}
+}
+
+func (s *Syncer) forget(rel string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.state.Files, rel)
+}
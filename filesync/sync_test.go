@@ -0,0 +1,395 @@
+/*
+Copyright 2021 The Go jAccount Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package filesync
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/dyweb/go-jaccount/jaccount"
+)
+
+// fakeStorage is an in-memory storage API holding files by absolute path.
+type fakeStorage struct {
+	mu    sync.Mutex
+	t     *testing.T
+	files map[string][]byte
+	now   time.Time
+	times map[string]time.Time
+
+	// listing, if set, is returned for every directory.
+	listing []*jaccount.FileInfo
+}
+
+func (f *fakeStorage) info(name string) *jaccount.FileInfo {
+	sum := sha256.Sum256(f.files[name])
+	return &jaccount.FileInfo{
+		Path:    name,
+		Name:    path.Base(name),
+		Size:    int64(len(f.files[name])),
+		ModTime: jaccount.Timestamp{Time: f.times[name]},
+		SHA256:  hex.EncodeToString(sum[:]),
+	}
+}
+
+// exists reports whether the directory exists, which it does if it has files.
+func (f *fakeStorage) exists(dir string) bool {
+	if dir == "/" || f.listing != nil {
+		return true
+	}
+	for p := range f.files {
+		if strings.HasPrefix(p, dir+"/") {
+			return true
+		}
+	}
+	return false
+}
+
+func (f *fakeStorage) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	endpoint := strings.SplitN(strings.TrimPrefix(r.URL.Path, "/v1/me/storage/"), "/", 2)
+	name := "/"
+	if len(endpoint) == 2 {
+		name = path.Clean("/" + endpoint[1])
+	}
+
+	var entities interface{}
+	switch endpoint[0] + " " + r.Method {
+	case "list GET":
+		if !f.exists(name) {
+			w.WriteHeader(http.StatusNotFound)
+			json.NewEncoder(w).Encode(&jaccount.Response{ErrNO: 404, Error: "not found"})
+			return
+		}
+		if f.listing != nil {
+			entities = f.listing
+			break
+		}
+		dirs := map[string]bool{}
+		var list []*jaccount.FileInfo
+		for p := range f.files {
+			if !strings.HasPrefix(p, name+"/") {
+				continue
+			}
+			rest := strings.TrimPrefix(p, name+"/")
+			if i := strings.Index(rest, "/"); i >= 0 {
+				if !dirs[rest[:i]] {
+					dirs[rest[:i]] = true
+					list = append(list, &jaccount.FileInfo{Path: path.Join(name, rest[:i]), Name: rest[:i], IsDir: true})
+				}
+				continue
+			}
+			list = append(list, f.info(p))
+		}
+		entities = list
+	case "folders POST":
+		entities = []*jaccount.FileInfo{{Path: name, IsDir: true}}
+	case "files PUT":
+		f.files[name], _ = ioutil.ReadAll(r.Body)
+		f.now = f.now.Add(time.Minute)
+		f.times[name] = f.now
+		entities = []*jaccount.FileInfo{f.info(name)}
+	case "files GET":
+		w.Header().Set("Content-Type", "application/octet-stream")
+		w.Write(f.files[name])
+		return
+	case "files DELETE":
+		delete(f.files, name)
+	default:
+		f.t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+	}
+
+	data, _ := json.Marshal(entities)
+	json.NewEncoder(w).Encode(&jaccount.Response{Entities: data})
+}
+
+func setup(t *testing.T) (*fakeStorage, *jaccount.Client, string, func()) {
+	storage := &fakeStorage{
+		t:     t,
+		files: map[string][]byte{},
+		now:   time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC),
+		times: map[string]time.Time{},
+	}
+	ts := httptest.NewServer(storage)
+
+	client := jaccount.NewClient(nil)
+	client.BaseURL, _ = url.Parse(ts.URL)
+
+	dir, err := ioutil.TempDir("", "filesync")
+	if err != nil {
+		t.Fatalf("error = %v", err)
+	}
+
+	return storage, client, dir, func() {
+		ts.Close()
+		os.RemoveAll(dir)
+	}
+}
+
+func writeFile(t *testing.T, name string, content string) {
+	if err := os.MkdirAll(filepath.Dir(name), 0755); err != nil {
+		t.Fatalf("error = %v", err)
+	}
+	if err := ioutil.WriteFile(name, []byte(content), 0644); err != nil {
+		t.Fatalf("error = %v", err)
+	}
+}
+
+func ops(actions []Action) []string {
+	var got []string
+	for _, a := range actions {
+		got = append(got, string(a.Op)+" "+a.Path)
+	}
+	return got
+}
+
+func checkOps(t *testing.T, name string, actions []Action, want ...string) {
+	got := ops(actions)
+	if strings.Join(got, ", ") != strings.Join(want, ", ") {
+		t.Errorf("%s actions = %v, want %v", name, got, want)
+	}
+}
+
+func TestSyncer_Push(t *testing.T) {
+	storage, client, dir, teardown := setup(t)
+	defer teardown()
+
+	local := filepath.Join(dir, "data")
+	writeFile(t, filepath.Join(local, "run1.csv"), "1,2,3\n")
+	writeFile(t, filepath.Join(local, "raw", "run1.bin"), "binary")
+	writeFile(t, filepath.Join(local, "notes.tmp"), "scratch")
+
+	s := &Syncer{
+		Storage:   client.Storage,
+		LocalDir:  local,
+		RemoteDir: "/backup",
+		Delete:    DeletePropagate,
+		Exclude:   []string{"*.tmp"},
+		StateFile: filepath.Join(dir, "state.json"),
+		DryRun:    true,
+	}
+
+	ctx := context.Background()
+	result, err := s.Run(ctx)
+	if err != nil {
+		t.Fatalf("Syncer.Run() dry run error = %v", err)
+	}
+	checkOps(t, "dry run", result.Actions, "upload raw/run1.bin", "upload run1.csv")
+	if len(storage.files) != 0 {
+		t.Errorf("dry run uploaded %v", storage.files)
+	}
+
+	s.DryRun = false
+	if _, err := s.Run(ctx); err != nil {
+		t.Fatalf("Syncer.Run() error = %v", err)
+	}
+	if string(storage.files["/backup/raw/run1.bin"]) != "binary" || len(storage.files) != 2 {
+		t.Errorf("remote files = %v", storage.files)
+	}
+
+	result, err = s.Run(ctx)
+	if err != nil {
+		t.Fatalf("Syncer.Run() error = %v", err)
+	}
+	checkOps(t, "unchanged", result.Actions)
+
+	os.Remove(filepath.Join(local, "run1.csv"))
+	writeFile(t, filepath.Join(local, "raw", "run1.bin"), "changed")
+	result, err = s.Run(ctx)
+	if err != nil {
+		t.Fatalf("Syncer.Run() error = %v", err)
+	}
+	checkOps(t, "changed", result.Actions, "upload raw/run1.bin", "delete-remote run1.csv")
+	if _, ok := storage.files["/backup/run1.csv"]; ok {
+		t.Error("deletion was not propagated")
+	}
+}
+
+func TestSyncer_TwoWay(t *testing.T) {
+	storage, client, dir, teardown := setup(t)
+	defer teardown()
+
+	local := filepath.Join(dir, "data")
+	writeFile(t, filepath.Join(local, "a.txt"), "local a")
+	writeFile(t, filepath.Join(local, "same.txt"), "same")
+	storage.files["/backup/b.txt"] = []byte("remote b")
+	storage.times["/backup/b.txt"] = storage.now
+	storage.files["/backup/same.txt"] = []byte("same")
+	storage.times["/backup/same.txt"] = storage.now
+
+	s := &Syncer{
+		Storage:   client.Storage,
+		LocalDir:  local,
+		RemoteDir: "/backup",
+		Direction: TwoWay,
+		StateFile: filepath.Join(local, ".sync-state.json"),
+	}
+
+	ctx := context.Background()
+	result, err := s.Run(ctx)
+	if err != nil {
+		t.Fatalf("Syncer.Run() error = %v", err)
+	}
+	checkOps(t, "first run", result.Actions, "upload a.txt", "download b.txt")
+
+	data, err := ioutil.ReadFile(filepath.Join(local, "b.txt"))
+	if err != nil || string(data) != "remote b" {
+		t.Errorf("downloaded b.txt = %q, %v", data, err)
+	}
+	if stat, _ := os.Stat(filepath.Join(local, "b.txt")); !stat.ModTime().Equal(storage.times["/backup/b.txt"]) {
+		t.Errorf("b.txt mtime = %v, want %v", stat.ModTime(), storage.times["/backup/b.txt"])
+	}
+
+	// Modify a.txt on both sides and b.txt remotely only.
+	writeFile(t, filepath.Join(local, "a.txt"), "local a, edited")
+	storage.files["/backup/a.txt"] = []byte("remote a, edited")
+	storage.times["/backup/a.txt"] = storage.now.Add(time.Hour)
+	storage.files["/backup/b.txt"] = []byte("remote b, edited")
+	storage.times["/backup/b.txt"] = storage.now.Add(time.Hour)
+
+	actions, conflicts, err := s.Plan(ctx)
+	if err != nil {
+		t.Fatalf("Syncer.Plan() error = %v", err)
+	}
+	checkOps(t, "plan", actions, "download b.txt")
+	checkOps(t, "conflicts", conflicts, "conflict a.txt")
+}
+
+func TestSyncer_MirrorTwoWay(t *testing.T) {
+	s := &Syncer{Direction: TwoWay, Delete: DeleteMirror}
+	if _, err := s.Run(context.Background()); err == nil {
+		t.Error("Syncer.Run() error = nil, want error")
+	}
+}
+
+func TestSyncer_MissingDir(t *testing.T) {
+	storage, client, dir, teardown := setup(t)
+	defer teardown()
+
+	local := filepath.Join(dir, "data")
+	writeFile(t, filepath.Join(local, "a.txt"), "local a")
+	storage.files["/backup/b.txt"] = []byte("remote b")
+	storage.times["/backup/b.txt"] = storage.now
+
+	ctx := context.Background()
+	tests := []struct {
+		name      string
+		local     string
+		remote    string
+		direction Direction
+		delete    DeletePolicy
+		wantErr   bool
+	}{
+		{"pull mirror from missing remote", local, "/missing", Pull, DeleteMirror, true},
+		{"push mirror from missing local", filepath.Join(dir, "missing"), "/backup", Push, DeleteMirror, true},
+		{"push to missing remote", local, "/missing", Push, DeleteMirror, false},
+		{"pull into missing local", filepath.Join(dir, "missing"), "/backup", Pull, DeleteMirror, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := &Syncer{
+				Storage:   client.Storage,
+				LocalDir:  tt.local,
+				RemoteDir: tt.remote,
+				Direction: tt.direction,
+				Delete:    tt.delete,
+				DryRun:    true,
+			}
+			result, err := s.Run(ctx)
+			if (err != nil) != tt.wantErr || tt.wantErr && !errors.Is(err, ErrMissingDir) {
+				t.Fatalf("Syncer.Run() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err == nil {
+				for _, a := range result.Actions {
+					if a.Op == OpDeleteLocal || a.Op == OpDeleteRemote {
+						t.Errorf("Syncer.Run() planned %s %s", a.Op, a.Path)
+					}
+				}
+			}
+		})
+	}
+
+	// After a two-way sync, a missing local directory must not propagate as
+	// the deletion of every file.
+	s := &Syncer{
+		Storage:   client.Storage,
+		LocalDir:  local,
+		RemoteDir: "/backup",
+		Direction: TwoWay,
+		Delete:    DeletePropagate,
+		StateFile: filepath.Join(dir, "state.json"),
+	}
+	if _, err := s.Run(ctx); err != nil {
+		t.Fatalf("Syncer.Run() error = %v", err)
+	}
+	if err := os.RemoveAll(local); err != nil {
+		t.Fatalf("error = %v", err)
+	}
+	if _, err := s.Run(ctx); !errors.Is(err, ErrMissingDir) {
+		t.Errorf("Syncer.Run() error = %v, want %v", err, ErrMissingDir)
+	}
+	if len(storage.files) != 2 {
+		t.Errorf("remote files = %v, want both kept", storage.files)
+	}
+}
+
+func TestSyncer_InvalidRemoteName(t *testing.T) {
+	storage, client, dir, teardown := setup(t)
+	defer teardown()
+
+	local := filepath.Join(dir, "data")
+	writeFile(t, filepath.Join(local, "a.txt"), "local a")
+	outside := filepath.Join(dir, "outside.txt")
+	storage.files["/outside.txt"] = []byte("evil")
+
+	for _, name := range []string{"..", ".", "../outside.txt", `..\outside.txt`} {
+		t.Run(name, func(t *testing.T) {
+			writeFile(t, outside, "keep")
+			storage.listing = []*jaccount.FileInfo{{Path: "/backup/" + name, Name: name, Size: 4}}
+			s := &Syncer{
+				Storage:   client.Storage,
+				LocalDir:  local,
+				RemoteDir: "/backup",
+				Direction: Pull,
+				Delete:    DeleteMirror,
+			}
+			if _, err := s.Run(context.Background()); err == nil {
+				t.Error("Syncer.Run() error = nil, want error")
+			}
+			if data, err := ioutil.ReadFile(outside); err != nil || string(data) != "keep" {
+				t.Errorf("outside.txt = %q, %v", data, err)
+			}
+		})
+	}
+}
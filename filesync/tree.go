@@ -0,0 +1,234 @@
+/*
+Copyright 2021 The Go jAccount Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package filesync
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/dyweb/go-jaccount/jaccount"
+)
+
+// file describes a file on one side of the sync.
+type file struct {
+	Size    int64
+	ModTime time.Time
+
+	// SHA256 is always known for remote files, and computed on demand for
+	// local ones.
+	SHA256 string
+}
+
+// matches reports whether the slash-separated relative path is selected by
+// the include and exclude globs. Globs without a slash match the base name,
+// those with one match the whole path.
+func (s *Syncer) matches(rel string, dir bool) bool {
+	for _, pattern := range s.Exclude {
+		if match(pattern, rel) {
+			return false
+		}
+	}
+
+	// Directories are always descended into, as files below them may be
+	// included.
+	if dir || len(s.Include) == 0 {
+		return true
+	}
+	for _, pattern := range s.Include {
+		if match(pattern, rel) {
+			return true
+		}
+	}
+	return false
+}
+
+func match(pattern string, rel string) bool {
+	name := rel
+	if !strings.Contains(pattern, "/") {
+		name = path.Base(rel)
+	}
+	ok, _ := path.Match(pattern, name)
+	return ok
+}
+
+// scanLocal returns the files below the local directory by relative path, and
+// whether the directory exists.
+func (s *Syncer) scanLocal() (map[string]*file, bool, error) {
+	files := make(map[string]*file)
+
+	var stateFile string
+	if s.StateFile != "" {
+		stateFile, _ = filepath.Abs(s.StateFile)
+	}
+	root, err := filepath.Abs(s.LocalDir)
+	if err != nil {
+		return nil, false, err
+	}
+
+	info, err := os.Stat(root)
+	if os.IsNotExist(err) {
+		return files, false, nil
+	}
+	if err != nil {
+		return nil, false, err
+	}
+	if !info.IsDir() {
+		return nil, false, fmt.Errorf("%s is not a directory", s.LocalDir)
+	}
+
+	err = filepath.Walk(root, func(name string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if name == root {
+			return nil
+		}
+
+		rel, err := filepath.Rel(root, name)
+		if err != nil {
+			return err
+		}
+		rel = filepath.ToSlash(rel)
+
+		if !s.matches(rel, info.IsDir()) || name == stateFile || isTempFile(info.Name()) {
+			if info.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if info.Mode().IsRegular() {
+			files[rel] = &file{Size: info.Size(), ModTime: info.ModTime()}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, false, err
+	}
+
+	return files, true, nil
+}
+
+// scanRemote returns the files below the remote directory by relative path,
+// and whether the directory exists.
+func (s *Syncer) scanRemote(ctx context.Context) (map[string]*file, bool, error) {
+	files := make(map[string]*file)
+
+	err := s.walkRemote(ctx, s.RemoteDir, "", files)
+	var e *jaccount.ErrorResponse
+	if errors.As(err, &e) && e.Response != nil && e.Response.StatusCode == http.StatusNotFound {
+		// The remote directory is created by the first upload.
+		return files, false, nil
+	}
+	if err != nil {
+		return nil, false, err
+	}
+
+	return files, true, nil
+}
+
+func (s *Syncer) walkRemote(ctx context.Context, dir string, rel string, files map[string]*file) error {
+	opts := &jaccount.ListOptions{}
+	for {
+		list, response, err := s.Storage.List(ctx, dir, opts)
+		if err != nil {
+			return err
+		}
+
+		for _, info := range list {
+			name := info.Name
+			if name == "" {
+				name = path.Base(info.Path)
+			}
+			if !validName(name) {
+				return fmt.Errorf("remote directory %s has an entry with invalid name %q", dir, name)
+			}
+			child := path.Join(rel, name)
+			if !s.matches(child, info.IsDir) {
+				continue
+			}
+
+			if info.IsDir {
+				if err := s.walkRemote(ctx, path.Join(dir, name), child, files); err != nil {
+					return err
+				}
+				continue
+			}
+			files[child] = &file{Size: info.Size, ModTime: info.ModTime.Time, SHA256: info.SHA256}
+		}
+
+		if response.NextToken == "" || len(list) == 0 {
+			return nil
+		}
+		opts.NextToken = response.NextToken
+	}
+}
+
+// validName reports whether the name of a remote entry is a single path
+// element, so that it cannot address a file outside of the local directory.
+func validName(name string) bool {
+	return name != "" && name != "." && name != ".." && !strings.ContainsAny(name, `/\`)
+}
+
+// localHash returns the checksum of the local file, reusing the one in the
+// state if the file has not been modified since.
+func (s *Syncer) localHash(rel string, f *file, prev *FileState) (string, error) {
+	if f.SHA256 != "" {
+		return f.SHA256, nil
+	}
+	if prev != nil && prev.SHA256 != "" && prev.Size == f.Size && prev.LocalModTime.Equal(f.ModTime) {
+		f.SHA256 = prev.SHA256
+		return f.SHA256, nil
+	}
+
+	r, err := os.Open(s.localPath(rel))
+	if err != nil {
+		return "", err
+	}
+	defer r.Close()
+
+	h := sha256.New()
+	if _, err := io.Copy(h, r); err != nil {
+		return "", err
+	}
+	f.SHA256 = hex.EncodeToString(h.Sum(nil))
+	return f.SHA256, nil
+}
+
+func (s *Syncer) localPath(rel string) string {
+	return filepath.Join(s.LocalDir, filepath.FromSlash(rel))
+}
+
+func (s *Syncer) remotePath(rel string) string {
+	return path.Join(s.RemoteDir, rel)
+}
+
+// tempPrefix marks partial downloads, which are not synced.
+const tempPrefix = ".jaccount-sync-"
+
+func isTempFile(name string) bool {
+	return strings.HasPrefix(name, tempPrefix)
+}
@@ -0,0 +1,179 @@
+/*
+Copyright 2021 The Go jAccount Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+/*
+Package forwardauth implements a forward authentication endpoint for reverse
+proxies such as nginx (auth_request), Traefik (ForwardAuth) and Caddy
+(forward_auth).
+
+The proxy asks the auth endpoint about every request. It answers 200 with the
+identity of the user in the X-Jaccount-* response headers when the user has a
+valid session and is allowed by the policy, and 401 or a redirect to the start
+endpoint otherwise. The start endpoint sends the user to jAccount, returning to
+the original URL after login.
+*/
+package forwardauth
+
+import (
+	"errors"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/dyweb/go-jaccount/policy"
+	"github.com/dyweb/go-jaccount/proxy"
+	"github.com/dyweb/go-jaccount/session"
+)
+
+// Default paths of the endpoints.
+const (
+	DefaultAuthPath  = "/auth"
+	DefaultStartPath = "/start"
+
+	// ReturnParam is the query parameter of the start endpoint that holds
+	// the URL to return to after login.
+	ReturnParam = "rd"
+)
+
+// Handler serves the forward authentication endpoints.
+type Handler struct {
+	// Session manages the login sessions. For absolute return URLs to be
+	// accepted, their hosts must be in Session.AllowedHosts.
+	Session *session.Manager
+
+	// Policy decides which users are allowed. Defaults to everyone with a
+	// jAccount.
+	Policy policy.Policy
+
+	// SigningKey, if set, is used to sign the identity headers.
+	SigningKey []byte
+
+	// StartURL is the URL of the start endpoint as reached by browsers. If
+	// set, the auth endpoint redirects unauthenticated browsers there
+	// instead of responding 401, as Traefik and Caddy expect. nginx
+	// auth_request only accepts 401, so leave it empty and use error_page.
+	StartURL string
+}
+
+// AuthHandler returns the auth endpoint.
+func (h *Handler) AuthHandler() http.Handler {
+	allow := h.Policy
+	if allow == nil {
+		allow = policy.AllowAll
+	}
+
+	return h.Session.Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		original := OriginalURL(r)
+
+		s, ok := session.FromContext(r.Context())
+		if !ok {
+			if h.StartURL == "" {
+				http.Error(w, http.StatusText(http.StatusUnauthorized), http.StatusUnauthorized)
+				return
+			}
+			http.Redirect(w, r, h.startURL(original), http.StatusFound)
+			return
+		}
+
+		err := policy.Check(r.Context(), allow)
+		if errors.Is(err, policy.ErrDenied) {
+			http.Error(w, http.StatusText(http.StatusForbidden), http.StatusForbidden)
+			return
+		}
+		if err != nil {
+			http.Error(w, "failed to evaluate policy", http.StatusInternalServerError)
+			return
+		}
+
+		method := r.Header.Get("X-Forwarded-Method")
+		if method == "" {
+			method = r.Header.Get("X-Original-Method")
+		}
+		if method == "" {
+			method = http.MethodGet
+		}
+
+		proxy.WriteHeaders(w.Header(), method, original.RequestURI(), proxy.IdentityFrom(s.IDToken, s.Profile), h.SigningKey)
+		w.WriteHeader(http.StatusOK)
+	}))
+}
+
+// StartHandler returns the start endpoint, which sends the user to the login
+// handler of the session. The return URL is taken from the ReturnParam query
+// parameter, or the original URL in the forwarded headers.
+func (h *Handler) StartHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		returnURL := r.URL.Query().Get(ReturnParam)
+		if returnURL == "" {
+			returnURL = OriginalURL(r).String()
+		}
+		http.Redirect(w, r, h.Session.LoginRedirectURL(returnURL), http.StatusFound)
+	})
+}
+
+func (h *Handler) startURL(original *url.URL) string {
+	u, err := url.Parse(h.StartURL)
+	if err != nil {
+		return h.StartURL
+	}
+	q := u.Query()
+	q.Set(ReturnParam, original.String())
+	u.RawQuery = q.Encode()
+	return u.String()
+}
+
+// OriginalURL returns the URL of the request the reverse proxy asks about. It
+// is taken from X-Original-URL as set for nginx, or X-Forwarded-Proto,
+// X-Forwarded-Host and X-Forwarded-Uri as set by Traefik and Caddy, falling
+// back to the URL of r itself.
+func OriginalURL(r *http.Request) *url.URL {
+	if raw := r.Header.Get("X-Original-URL"); raw != "" {
+		if u, err := url.Parse(raw); err == nil {
+			return u
+		}
+	}
+
+	u := &url.URL{
+		Scheme: "http",
+		Host:   r.Host,
+		Path:   r.URL.Path,
+	}
+	u.RawQuery = r.URL.RawQuery
+	if r.TLS != nil {
+		u.Scheme = "https"
+	}
+
+	if proto := r.Header.Get("X-Forwarded-Proto"); proto == "http" || proto == "https" {
+		u.Scheme = proto
+	}
+	if host := r.Header.Get("X-Forwarded-Host"); host != "" {
+		u.Host = host
+	}
+
+	uri := r.Header.Get("X-Forwarded-Uri")
+	if uri == "" {
+		uri = r.Header.Get("X-Original-URI")
+	}
+	if strings.HasPrefix(uri, "/") {
+		if parsed, err := url.ParseRequestURI(uri); err == nil {
+			u.Path = parsed.Path
+			u.RawPath = parsed.RawPath
+			u.RawQuery = parsed.RawQuery
+		}
+	}
+
+	return u
+}
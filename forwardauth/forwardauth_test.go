@@ -0,0 +1,156 @@
+/*
+Copyright 2021 The Go jAccount Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package forwardauth
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/dyweb/go-jaccount/jaccount"
+	"github.com/dyweb/go-jaccount/proxy"
+	"github.com/dyweb/go-jaccount/session"
+	"golang.org/x/oauth2"
+)
+
+func TestOriginalURL(t *testing.T) {
+	tests := []struct {
+		name    string
+		headers map[string]string
+		want    string
+	}{
+		{"nginx", map[string]string{"X-Original-URL": "https://app.example.com/page?q=1"}, "https://app.example.com/page?q=1"},
+		{"traefik", map[string]string{"X-Forwarded-Proto": "https", "X-Forwarded-Host": "app.example.com", "X-Forwarded-Uri": "/page?q=1"}, "https://app.example.com/page?q=1"},
+		{"none", nil, "http://auth.example.com/auth"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "http://auth.example.com/auth", nil)
+			for k, v := range tt.headers {
+				req.Header.Set(k, v)
+			}
+			if got := OriginalURL(req).String(); got != tt.want {
+				t.Errorf("OriginalURL() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestHandler(t *testing.T) {
+	key := []byte("key")
+	backend := &session.MemoryBackend{}
+	now := time.Now()
+	backend.Set(context.Background(), &session.Session{
+		ID:        "student",
+		IDToken:   &jaccount.IDToken{Subject: "student", Type: jaccount.STUDENT, Expiry: now.Add(time.Hour)},
+		CreatedAt: now,
+		LastSeen:  now,
+	}, now.Add(time.Hour))
+
+	manager := &session.Manager{
+		Config:       &oauth2.Config{ClientID: "client", Endpoint: jaccount.Endpoint},
+		Store:        &session.ServerStore{Backend: backend},
+		LoginURL:     "https://auth.example.com/oauth2/login",
+		AllowedHosts: []string{".example.com"},
+	}
+
+	tests := []struct {
+		name         string
+		startURL     string
+		session      string
+		wantStatus   int
+		wantLocation string
+	}{
+		{"authenticated", "", "student", http.StatusOK, ""},
+		{"unauthenticated", "", "", http.StatusUnauthorized, ""},
+		{"redirect", "https://auth.example.com/start", "", http.StatusFound, "https://auth.example.com/start?rd=https%3A%2F%2Fapp.example.com%2Fpage"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := &Handler{Session: manager, SigningKey: key, StartURL: tt.startURL}
+
+			req := httptest.NewRequest(http.MethodGet, "/auth", nil)
+			req.Header.Set("X-Forwarded-Proto", "https")
+			req.Header.Set("X-Forwarded-Host", "app.example.com")
+			req.Header.Set("X-Forwarded-Uri", "/page")
+			if tt.session != "" {
+				req.AddCookie(&http.Cookie{Name: session.DefaultCookieName, Value: tt.session})
+			}
+
+			rec := httptest.NewRecorder()
+			h.AuthHandler().ServeHTTP(rec, req)
+
+			if rec.Code != tt.wantStatus {
+				t.Errorf("AuthHandler() status = %d, want %d", rec.Code, tt.wantStatus)
+			}
+			if got := rec.Header().Get("Location"); got != tt.wantLocation {
+				t.Errorf("AuthHandler() Location = %q, want %q", got, tt.wantLocation)
+			}
+			if tt.wantStatus != http.StatusOK {
+				return
+			}
+
+			// The upstream receives the headers on the original request.
+			upstreamReq := httptest.NewRequest(http.MethodGet, "https://app.example.com/page", nil)
+			for _, name := range []string{proxy.HeaderAccount, proxy.HeaderName, proxy.HeaderCode, proxy.HeaderType, proxy.HeaderOrg, proxy.HeaderTimestamp, proxy.HeaderSignature} {
+				upstreamReq.Header.Set(name, rec.Header().Get(name))
+			}
+			id, err := proxy.VerifyHeaders(upstreamReq, key, time.Minute)
+			if err != nil {
+				t.Errorf("proxy.VerifyHeaders() error = %v", err)
+				return
+			}
+			if id.Account != "student" || id.Type != jaccount.STUDENT {
+				t.Errorf("AuthHandler() identity = %+v", id)
+			}
+		})
+	}
+
+	h := &Handler{Session: manager}
+	req := httptest.NewRequest(http.MethodGet, "/start", nil)
+	req.Header.Set("X-Original-URL", "https://app.example.com/page")
+	rec := httptest.NewRecorder()
+	h.StartHandler().ServeHTTP(rec, req)
+
+	want := "https://auth.example.com/oauth2/login?return=https%3A%2F%2Fapp.example.com%2Fpage"
+	if got := rec.Header().Get("Location"); got != want {
+		t.Errorf("StartHandler() Location = %q, want %q", got, want)
+	}
+
+	// The login handler accepts the return URL of an allowed host only.
+	for returnURL, want := range map[string]string{
+		"https://app.example.com/page": "https://app.example.com/page",
+		"https://evil.com/page":        "/",
+	} {
+		rec := httptest.NewRecorder()
+		manager.LogoutHandler().ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/logout?return="+returnURL, nil))
+		if got := rec.Header().Get("Location"); got != want {
+			t.Errorf("LogoutHandler() Location = %q, want %q", got, want)
+		}
+	}
+
+	// Cross-site links cannot log users out.
+	rec = httptest.NewRecorder()
+	manager.LogoutHandler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/logout", nil))
+	if rec.Code != http.StatusMethodNotAllowed {
+		t.Errorf("LogoutHandler() status = %d for GET, want %d", rec.Code, http.StatusMethodNotAllowed)
+	}
+}
@@ -3,7 +3,12 @@ module github.com/dyweb/go-jaccount
 go 1.16
 
 require (
+	github.com/gin-gonic/gin v1.7.7
+	github.com/go-chi/chi/v5 v5.0.8
 	github.com/google/go-querystring v1.1.0
+	github.com/labstack/echo/v4 v4.6.3
 	golang.org/x/oauth2 v0.0.0-20210628180205-a41e5a781914
+	google.golang.org/grpc v1.46.2
 	gopkg.in/square/go-jose.v2 v2.6.0
+	gopkg.in/yaml.v3 v3.0.1
 )
@@ -0,0 +1,148 @@
+/*
+Copyright 2021 The Go jAccount Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package grpcauth
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/dyweb/go-jaccount/bearer"
+	"github.com/dyweb/go-jaccount/jaccount"
+	"github.com/dyweb/go-jaccount/policy"
+	"golang.org/x/oauth2"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+func TestInterceptor_Unary(t *testing.T) {
+	interceptor := &Interceptor{
+		Authenticator: &bearer.Authenticator{
+			Validator: bearer.ValidatorFunc(func(ctx context.Context, token string) (*bearer.Result, error) {
+				switch token {
+				case "student":
+					return &bearer.Result{
+						IDToken: &jaccount.IDToken{Type: jaccount.STUDENT},
+						Scopes:  jaccount.NewScopes(jaccount.ScopeBasic),
+					}, nil
+				case "faculty":
+					return &bearer.Result{IDToken: &jaccount.IDToken{Type: jaccount.FACULTY}}, nil
+				default:
+					return nil, &bearer.Error{Code: bearer.ErrorInvalidToken}
+				}
+			}),
+		},
+		Scopes: map[string][]string{
+			"/test.Service/Lessons": {jaccount.ScopeLessons},
+		},
+		Policies: map[string]policy.Policy{
+			"/test.Service/Grade": policy.RequireType(jaccount.FACULTY),
+		},
+		Skip: func(fullMethod string) bool {
+			return fullMethod == "/grpc.health.v1.Health/Check"
+		},
+	}
+
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return nil, nil
+	}
+
+	tests := []struct {
+		name     string
+		method   string
+		md       metadata.MD
+		wantCode codes.Code
+	}{
+		{"authenticated", "/test.Service/Get", metadata.Pairs("authorization", "Bearer student"), codes.OK},
+		{"missing token", "/test.Service/Get", metadata.MD{}, codes.Unauthenticated},
+		{"invalid token", "/test.Service/Get", metadata.Pairs("authorization", "Bearer invalid"), codes.Unauthenticated},
+		{"insufficient scope", "/test.Service/Lessons", metadata.Pairs("authorization", "Bearer student"), codes.PermissionDenied},
+		{"unknown scopes", "/test.Service/Lessons", metadata.Pairs("authorization", "Bearer faculty"), codes.PermissionDenied},
+		{"policy allowed", "/test.Service/Grade", metadata.Pairs("authorization", "Bearer faculty"), codes.OK},
+		{"policy denied", "/test.Service/Grade", metadata.Pairs("authorization", "Bearer student"), codes.PermissionDenied},
+		{"skipped", "/grpc.health.v1.Health/Check", metadata.MD{}, codes.OK},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := metadata.NewIncomingContext(context.Background(), tt.md)
+			_, err := interceptor.Unary()(ctx, nil, &grpc.UnaryServerInfo{FullMethod: tt.method}, handler)
+			if got := status.Code(err); got != tt.wantCode {
+				t.Errorf("Interceptor.Unary() code = %v, want %v (%v)", got, tt.wantCode, err)
+			}
+		})
+	}
+}
+
+func TestUnaryClientInterceptor(t *testing.T) {
+	ts := oauth2.StaticTokenSource(&oauth2.Token{AccessToken: "token"})
+
+	invoker := func(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, opts ...grpc.CallOption) error {
+		md, _ := metadata.FromOutgoingContext(ctx)
+		if got := md.Get("authorization"); len(got) != 1 || got[0] != "Bearer token" {
+			t.Errorf("authorization metadata = %v, want %q", got, "Bearer token")
+		}
+		return nil
+	}
+
+	if err := UnaryClientInterceptor(ts)(context.Background(), "/test.Service/Get", nil, nil, nil, invoker); err != nil {
+		t.Errorf("UnaryClientInterceptor() error = %v", err)
+	}
+}
+
+type tokenSourceFunc func() (*oauth2.Token, error)
+
+func (f tokenSourceFunc) Token() (*oauth2.Token, error) {
+	return f()
+}
+
+func TestStreamClientInterceptor(t *testing.T) {
+	tests := []struct {
+		name     string
+		ts       oauth2.TokenSource
+		wantCode codes.Code
+	}{
+		{"token", oauth2.StaticTokenSource(&oauth2.Token{AccessToken: "token"}), codes.OK},
+		{"token source error", tokenSourceFunc(func() (*oauth2.Token, error) {
+			return nil, errors.New("refresh failed")
+		}), codes.Unauthenticated},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			called := false
+			streamer := func(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn, method string, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+				called = true
+				md, _ := metadata.FromOutgoingContext(ctx)
+				if got := md.Get("authorization"); len(got) != 1 || got[0] != "Bearer token" {
+					t.Errorf("authorization metadata = %v, want %q", got, "Bearer token")
+				}
+				return nil, nil
+			}
+
+			_, err := StreamClientInterceptor(tt.ts)(context.Background(), &grpc.StreamDesc{}, nil, "/test.Service/Watch", streamer)
+			if got := status.Code(err); got != tt.wantCode {
+				t.Errorf("StreamClientInterceptor() code = %v, want %v (%v)", got, tt.wantCode, err)
+			}
+			if called != (tt.wantCode == codes.OK) {
+				t.Errorf("StreamClientInterceptor() called streamer = %v", called)
+			}
+		})
+	}
+}
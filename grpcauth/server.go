@@ -0,0 +1,167 @@
+/*
+Copyright 2021 The Go jAccount Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+/*
+Package grpcauth provides gRPC interceptors that authenticate RPCs with jAccount
+bearer tokens.
+
+Server interceptors read the token from the "authorization" metadata, validate
+it with a bearer.Authenticator, store the identity of the user in the context
+and enforce per-method scopes and policies. Client interceptors attach tokens
+from an oauth2.TokenSource.
+*/
+package grpcauth
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"github.com/dyweb/go-jaccount/bearer"
+	"github.com/dyweb/go-jaccount/jaccount"
+	"github.com/dyweb/go-jaccount/policy"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+const authorizationKey = "authorization"
+
+// Interceptor authenticates and authorizes RPCs.
+type Interceptor struct {
+	// Authenticator validates the bearer tokens, typically with
+	// bearer.Introspection.
+	Authenticator *bearer.Authenticator
+
+	// Scopes maps full method names, such as "/pkg.Service/Method", to the
+	// scopes the token must have been granted to call them. Calls to these
+	// methods are denied if the validator cannot report the granted scopes.
+	Scopes map[string][]string
+
+	// Policies maps full method names to the policy enforced on them.
+	// Methods without a policy are subject to DefaultPolicy, if any.
+	Policies      map[string]policy.Policy
+	DefaultPolicy policy.Policy
+
+	// Skip, if set, exempts methods from authentication, for example
+	// health checks.
+	Skip func(fullMethod string) bool
+}
+
+// Unary returns a unary server interceptor.
+func (i *Interceptor) Unary() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		ctx, err := i.authorize(ctx, info.FullMethod)
+		if err != nil {
+			return nil, err
+		}
+		return handler(ctx, req)
+	}
+}
+
+// Stream returns a stream server interceptor.
+func (i *Interceptor) Stream() grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		ctx, err := i.authorize(ss.Context(), info.FullMethod)
+		if err != nil {
+			return err
+		}
+		return handler(srv, &serverStream{ServerStream: ss, ctx: ctx})
+	}
+}
+
+func (i *Interceptor) authorize(ctx context.Context, fullMethod string) (context.Context, error) {
+	if i.Skip != nil && i.Skip(fullMethod) {
+		return ctx, nil
+	}
+
+	token, err := TokenFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := i.Authenticator.Authenticate(ctx, token)
+	if err != nil {
+		return nil, toStatus(err)
+	}
+
+	if required := i.Scopes[fullMethod]; len(required) > 0 {
+		if result.Scopes == nil {
+			return nil, status.Error(codes.PermissionDenied, "insufficient scope: scopes of the token are unknown")
+		}
+		if missing := result.Scopes.Missing(jaccount.NewScopes(required...)); len(missing) > 0 {
+			return nil, status.Errorf(codes.PermissionDenied, "insufficient scope: missing %s", strings.Join(missing, " "))
+		}
+	}
+
+	ctx = result.NewContext(ctx)
+
+	p, ok := i.Policies[fullMethod]
+	if !ok {
+		p = i.DefaultPolicy
+	}
+	if p != nil {
+		err := policy.Check(ctx, p)
+		if errors.Is(err, policy.ErrDenied) {
+			return nil, status.Error(codes.PermissionDenied, err.Error())
+		}
+		if err != nil {
+			return nil, status.Errorf(codes.Internal, "failed to evaluate policy: %s", err)
+		}
+	}
+
+	return ctx, nil
+}
+
+// TokenFromContext returns the bearer token in the incoming metadata of ctx.
+func TokenFromContext(ctx context.Context) (string, error) {
+	md, _ := metadata.FromIncomingContext(ctx)
+	values := md.Get(authorizationKey)
+	if len(values) == 0 {
+		return "", status.Error(codes.Unauthenticated, "bearer token required")
+	}
+	if len(values) > 1 {
+		return "", status.Error(codes.Unauthenticated, "multiple authorization metadata")
+	}
+
+	token, err := bearer.ParseAuthorization(values[0])
+	if err != nil {
+		return "", toStatus(err)
+	}
+	return token, nil
+}
+
+func toStatus(err error) error {
+	var e *bearer.Error
+	if !errors.As(err, &e) {
+		return status.Errorf(codes.Unavailable, "failed to validate bearer token: %s", err)
+	}
+	if e.Code == bearer.ErrorInsufficientScope {
+		return status.Error(codes.PermissionDenied, e.Error())
+	}
+	return status.Error(codes.Unauthenticated, e.Error())
+}
+
+// serverStream overrides the context of a grpc.ServerStream.
+type serverStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *serverStream) Context() context.Context {
+	return s.ctx
+}
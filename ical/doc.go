@@ -0,0 +1,29 @@
+/*
+Copyright 2021 The Go jAccount Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+/*
+Package ical exports the course schedule of a jAccount user to iCalendar
+(RFC 5545), so that it can be imported into calendar applications.
+
+A Schedule expands the weekly time slots of the lessons returned by
+LessonsService into one event per class meeting, using the calendar of the
+term from package academic to find the dates, skipping holidays and moving
+classes to make-up days, and a bell schedule to find the clock times of the
+sections. Exams returned by ExamsService become events as well. Events are
+written into a Calendar, which carries the Asia/Shanghai time zone definition
+and optional reminders.
+*/
+package ical
@@ -0,0 +1,212 @@
+/*
+Copyright 2021 The Go jAccount Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ical
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/dyweb/go-jaccount/academic"
+)
+
+// Shanghai is the time zone of the events.
+var Shanghai = academic.Shanghai
+
+// TZID is the identifier of the time zone in the calendar.
+const TZID = "Asia/Shanghai"
+
+// Calendar is an iCalendar object holding events.
+type Calendar struct {
+	// Name is shown by calendar applications as the name of the calendar.
+	Name   string
+	Events []*Event
+
+	// Stamp is the time the calendar is generated at. It defaults to the
+	// current time.
+	Stamp time.Time
+}
+
+// Event is an event in a Calendar.
+type Event struct {
+	// UID identifies the event, so that importing an updated calendar
+	// replaces the events imported before.
+	UID         string
+	Summary     string
+	Location    string
+	Description string
+	Start       time.Time
+	End         time.Time
+
+	// Alarms are reminders shown the given durations before the start.
+	Alarms []time.Duration
+}
+
+// WriteTo writes the calendar in iCalendar format.
+func (c *Calendar) WriteTo(w io.Writer) (int64, error) {
+	cw := &contentWriter{w: bufio.NewWriter(w)}
+
+	stamp := c.Stamp
+	if stamp.IsZero() {
+		stamp = time.Now()
+	}
+
+	cw.line("BEGIN:VCALENDAR")
+	cw.line("VERSION:2.0")
+	cw.line("PRODID:-//dyweb//go-jaccount//EN")
+	cw.line("CALSCALE:GREGORIAN")
+	cw.line("METHOD:PUBLISH")
+	if c.Name != "" {
+		cw.line("X-WR-CALNAME:" + escape(c.Name))
+	}
+	cw.line("X-WR-TIMEZONE:" + TZID)
+
+	cw.line("BEGIN:VTIMEZONE")
+	cw.line("TZID:" + TZID)
+	cw.line("BEGIN:STANDARD")
+	cw.line("DTSTART:19700101T000000")
+	cw.line("TZOFFSETFROM:+0800")
+	cw.line("TZOFFSETTO:+0800")
+	cw.line("TZNAME:CST")
+	cw.line("END:STANDARD")
+	cw.line("END:VTIMEZONE")
+
+	for _, e := range c.Events {
+		cw.line("BEGIN:VEVENT")
+		cw.line("UID:" + e.UID)
+		cw.line("DTSTAMP:" + stamp.UTC().Format("20060102T150405Z"))
+		cw.line("DTSTART;TZID=" + TZID + ":" + localTime(e.Start))
+		cw.line("DTEND;TZID=" + TZID + ":" + localTime(e.End))
+		cw.line("SUMMARY:" + escape(e.Summary))
+		if e.Location != "" {
+			cw.line("LOCATION:" + escape(e.Location))
+		}
+		if e.Description != "" {
+			cw.line("DESCRIPTION:" + escape(e.Description))
+		}
+		for _, before := range e.Alarms {
+			cw.line("BEGIN:VALARM")
+			cw.line("ACTION:DISPLAY")
+			cw.line("DESCRIPTION:" + escape(e.Summary))
+			cw.line("TRIGGER:-" + duration(before))
+			cw.line("END:VALARM")
+		}
+		cw.line("END:VEVENT")
+	}
+
+	cw.line("END:VCALENDAR")
+
+	if cw.err == nil {
+		cw.err = cw.w.Flush()
+	}
+	return cw.n, cw.err
+}
+
+func localTime(t time.Time) string {
+	return t.In(Shanghai).Format("20060102T150405")
+}
+
+// duration formats a non-negative duration as an iCalendar duration value.
+func duration(d time.Duration) string {
+	if d < 0 {
+		d = -d
+	}
+	days := d / (24 * time.Hour)
+	d -= days * 24 * time.Hour
+
+	var b strings.Builder
+	b.WriteString("P")
+	if days > 0 {
+		fmt.Fprintf(&b, "%dD", days)
+	}
+	if d > 0 || days == 0 {
+		b.WriteString("T")
+		h, m, s := d/time.Hour, d%time.Hour/time.Minute, d%time.Minute/time.Second
+		if h > 0 {
+			fmt.Fprintf(&b, "%dH", h)
+		}
+		if m > 0 {
+			fmt.Fprintf(&b, "%dM", m)
+		}
+		if s > 0 || h == 0 && m == 0 {
+			fmt.Fprintf(&b, "%dS", s)
+		}
+	}
+	return b.String()
+}
+
+// escape escapes a text value.
+func escape(s string) string {
+	return strings.NewReplacer(
+		"\\", "\\\\",
+		";", "\\;",
+		",", "\\,",
+		"\r\n", "\\n",
+		"\n", "\\n",
+	).Replace(s)
+}
+
+// contentWriter writes content lines, folding them at 75 octets without
+// splitting UTF-8 sequences. The first error is kept and later writes are
+// skipped.
+type contentWriter struct {
+	w   *bufio.Writer
+	n   int64
+	err error
+}
+
+func (cw *contentWriter) line(s string) {
+	const limit = 75
+
+	first := true
+	for cw.err == nil {
+		max := limit
+		if !first {
+			// Continuation lines start with a space.
+			max--
+		}
+		if len(s) <= max {
+			cw.write(s, first)
+			cw.write("\r\n", true)
+			return
+		}
+
+		cut := max
+		for cut > 0 && !utf8.RuneStart(s[cut]) {
+			cut--
+		}
+		cw.write(s[:cut], first)
+		cw.write("\r\n", true)
+		s = s[cut:]
+		first = false
+	}
+}
+
+func (cw *contentWriter) write(s string, first bool) {
+	if cw.err != nil {
+		return
+	}
+	if !first {
+		s = " " + s
+	}
+	n, err := cw.w.WriteString(s)
+	cw.n += int64(n)
+	cw.err = err
+}
@@ -0,0 +1,166 @@
+/*
+Copyright 2021 The Go jAccount Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ical
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+	"unicode/utf8"
+
+	"github.com/dyweb/go-jaccount/academic"
+	"github.com/dyweb/go-jaccount/jaccount"
+)
+
+func TestSchedule_LessonEvents(t *testing.T) {
+	lesson := &jaccount.Lesson{
+		CourseCode: "CS1501",
+		CourseName: "程序设计",
+		Term:       jaccount.Term{Year: 2026, Semester: jaccount.Fall},
+		Teachers:   []*jaccount.Teacher{{Name: "张老师"}},
+		Slots: []*jaccount.TimeSlot{{
+			Weekday:      time.Wednesday,
+			StartSection: 3,
+			EndSection:   4,
+			Weeks:        jaccount.Weeks{{Start: 1, End: 5, Parity: jaccount.OddWeeks}},
+			Classroom:    "东上院102",
+		}},
+	}
+
+	// The term starts on a Monday, but any day in the first week will do.
+	// Classes of 2026-09-30 move to the make-up day 2026-10-10.
+	s := &Schedule{Term: &academic.TermCalendar{
+		Start:      academic.D(2026, 9, 16),
+		Weeks:      16,
+		MakeupDays: []academic.MakeupDay{{Date: academic.D(2026, 10, 10), Follows: academic.D(2026, 9, 30)}},
+	}}
+	events, err := s.LessonEvents([]*jaccount.Lesson{lesson})
+	if err != nil {
+		t.Fatalf("Schedule.LessonEvents() error = %v", err)
+	}
+
+	want := []time.Time{
+		time.Date(2026, 9, 16, 10, 0, 0, 0, Shanghai),
+		time.Date(2026, 10, 10, 10, 0, 0, 0, Shanghai),
+		time.Date(2026, 10, 14, 10, 0, 0, 0, Shanghai),
+	}
+	if len(events) != len(want) {
+		t.Fatalf("Schedule.LessonEvents() = %d events, want %d", len(events), len(want))
+	}
+	for i, e := range events {
+		if !e.Start.Equal(want[i]) || !e.End.Equal(want[i].Add(100*time.Minute)) {
+			t.Errorf("event %d = %v-%v, want start %v", i, e.Start, e.End, want[i])
+		}
+	}
+	if events[0].UID == events[1].UID {
+		t.Error("events share a UID")
+	}
+
+	again, _ := s.LessonEvents([]*jaccount.Lesson{lesson})
+	if again[0].UID != events[0].UID {
+		t.Error("UID is not stable")
+	}
+
+	lesson.Slots[0].EndSection = 15
+	if _, err := s.LessonEvents([]*jaccount.Lesson{lesson}); err == nil {
+		t.Error("Schedule.LessonEvents() error = nil for section 15")
+	}
+}
+
+func TestSchedule_ExamEvents(t *testing.T) {
+	start := time.Date(2027, 1, 11, 9, 0, 0, 0, Shanghai)
+	s := &Schedule{Reminders: []time.Duration{24 * time.Hour}}
+	events := s.ExamEvents([]*jaccount.Exam{{
+		CourseCode: "MA1201",
+		CourseName: "高等数学",
+		Start:      jaccount.Timestamp{Time: start},
+		End:        jaccount.Timestamp{Time: start.Add(2 * time.Hour)},
+		Location:   "东上院301",
+		Seat:       "23",
+	}})
+
+	if len(events) != 1 {
+		t.Fatalf("Schedule.ExamEvents() = %d events, want 1", len(events))
+	}
+	e := events[0]
+	if e.Summary != "高等数学考试" || e.Location != "东上院301" || !e.Start.Equal(start) || len(e.Alarms) != 1 {
+		t.Errorf("Schedule.ExamEvents() = %+v", e)
+	}
+	if !strings.Contains(e.Description, "23") {
+		t.Errorf("Schedule.ExamEvents() description = %q, want the seat", e.Description)
+	}
+
+	rescheduled := s.ExamEvents([]*jaccount.Exam{{
+		CourseCode: "MA1201",
+		CourseName: "高等数学",
+		Start:      jaccount.Timestamp{Time: start.Add(48 * time.Hour)},
+		End:        jaccount.Timestamp{Time: start.Add(50 * time.Hour)},
+	}})
+	if rescheduled[0].UID != e.UID {
+		t.Error("UID changed when the exam was rescheduled")
+	}
+}
+
+func TestCalendar_WriteTo(t *testing.T) {
+	start := time.Date(2026, 9, 16, 10, 0, 0, 0, Shanghai)
+	c := &Calendar{
+		Name:  "课表",
+		Stamp: time.Date(2026, 9, 1, 0, 0, 0, 0, time.UTC),
+		Events: []*Event{{
+			UID:         "1@jaccount.sjtu.edu.cn",
+			Summary:     "程序设计; Lab, part 1",
+			Location:    strings.Repeat("东上院", 10),
+			Description: "CS1501\n第1周",
+			Start:       start,
+			End:         start.Add(100 * time.Minute),
+			Alarms:      []time.Duration{15 * time.Minute, 24*time.Hour + 90*time.Minute},
+		}},
+	}
+
+	var buf bytes.Buffer
+	if _, err := c.WriteTo(&buf); err != nil {
+		t.Fatalf("Calendar.WriteTo() error = %v", err)
+	}
+	out := buf.String()
+
+	for _, want := range []string{
+		"BEGIN:VCALENDAR\r\nVERSION:2.0\r\n",
+		"BEGIN:VTIMEZONE\r\nTZID:Asia/Shanghai\r\n",
+		"DTSTAMP:20260901T000000Z\r\n",
+		"DTSTART;TZID=Asia/Shanghai:20260916T100000\r\n",
+		"DTEND;TZID=Asia/Shanghai:20260916T114000\r\n",
+		"SUMMARY:程序设计\\; Lab\\, part 1\r\n",
+		"DESCRIPTION:CS1501\\n第1周\r\n",
+		"TRIGGER:-PT15M\r\n",
+		"TRIGGER:-P1DT1H30M\r\n",
+		"END:VCALENDAR\r\n",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("Calendar.WriteTo() output lacks %q", want)
+		}
+	}
+
+	for _, line := range strings.Split(out, "\r\n") {
+		if len(line) > 75 || !utf8.ValidString(line) {
+			t.Errorf("line not folded at a character boundary within 75 octets: %q", line)
+		}
+	}
+	if !strings.Contains(out, "\r\n 上院") {
+		t.Error("long line is not folded")
+	}
+}
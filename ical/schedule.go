@@ -0,0 +1,135 @@
+/*
+Copyright 2021 The Go jAccount Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ical
+
+import (
+	"crypto/sha1"
+	"encoding/hex"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/dyweb/go-jaccount/academic"
+	"github.com/dyweb/go-jaccount/jaccount"
+)
+
+// Schedule expands lessons into events.
+type Schedule struct {
+	// Term is the calendar of the term. Classes are left out on holidays
+	// and moved to make-up days.
+	Term *academic.TermCalendar
+
+	// Bells defaults to academic.SJTUBells.
+	Bells academic.BellSchedule
+
+	// Reminders are added to every event as alarms.
+	Reminders []time.Duration
+}
+
+// LessonEvents returns one event for every meeting of the lessons, in order
+// of the lessons and their time slots.
+func (s *Schedule) LessonEvents(lessons []*jaccount.Lesson) ([]*Event, error) {
+	if s.Term == nil {
+		return nil, fmt.Errorf("term calendar not set")
+	}
+	bells := s.Bells
+	if bells == nil {
+		bells = academic.SJTUBells
+	}
+
+	var events []*Event
+	for _, lesson := range lessons {
+		for _, slot := range lesson.Slots {
+			for _, week := range slot.Weeks.List() {
+				meetings, err := s.Term.ClassMeetings(bells, week, slot.Weekday, slot.StartSection, slot.EndSection)
+				if err != nil {
+					return nil, fmt.Errorf("%s: %v", lesson.CourseCode, err)
+				}
+
+				for _, m := range meetings {
+					events = append(events, &Event{
+						UID:         lessonUID(lesson, m.Start, slot.StartSection),
+						Summary:     lesson.CourseName,
+						Location:    slot.Classroom,
+						Description: lessonDescription(lesson, week),
+						Start:       m.Start,
+						End:         m.End,
+						Alarms:      s.Reminders,
+					})
+				}
+			}
+		}
+	}
+	return events, nil
+}
+
+// ExamEvents returns one event for every exam.
+func (s *Schedule) ExamEvents(exams []*jaccount.Exam) []*Event {
+	events := make([]*Event, 0, len(exams))
+	for _, exam := range exams {
+		summary := exam.CourseName + "考试"
+		if exam.Name != "" {
+			summary = exam.CourseName + " " + exam.Name
+		}
+
+		var description []string
+		if exam.Seat != "" {
+			description = append(description, "座位号："+exam.Seat)
+		}
+		if exam.CourseCode != "" {
+			description = append(description, exam.CourseCode)
+		}
+
+		events = append(events, &Event{
+			UID:         examUID(exam),
+			Summary:     summary,
+			Location:    exam.Location,
+			Description: strings.Join(description, "\n"),
+			Start:       exam.Start.Time,
+			End:         exam.End.Time,
+			Alarms:      s.Reminders,
+		})
+	}
+	return events
+}
+
+// lessonUID returns a UID that is stable across exports of the same meeting.
+func lessonUID(lesson *jaccount.Lesson, day time.Time, section int) string {
+	key := fmt.Sprintf("%s/%s/%s/%s/%d", lesson.Term, lesson.CourseCode, lesson.ClassCode, day.In(Shanghai).Format("20060102"), section)
+	sum := sha1.Sum([]byte(key))
+	return hex.EncodeToString(sum[:]) + "@jaccount.sjtu.edu.cn"
+}
+
+// examUID returns a UID that is stable across exports of the same exam, even
+// if it is rescheduled, so that calendar clients update the event in place.
+func examUID(exam *jaccount.Exam) string {
+	key := fmt.Sprintf("exam/%s/%s/%s/%s", exam.Term, exam.CourseCode, exam.Type, exam.Name)
+	sum := sha1.Sum([]byte(key))
+	return hex.EncodeToString(sum[:]) + "@jaccount.sjtu.edu.cn"
+}
+
+func lessonDescription(lesson *jaccount.Lesson, week int) string {
+	lines := []string{lesson.CourseCode, fmt.Sprintf("第%d周", week)}
+	var teachers []string
+	for _, t := range lesson.Teachers {
+		teachers = append(teachers, t.Name)
+	}
+	if len(teachers) > 0 {
+		lines = append(lines, strings.Join(teachers, "、"))
+	}
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,153 @@
+/*
+Copyright 2021 The Go jAccount Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package jaccount
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Amount is an exact amount of money in yuan, stored as an integer number of
+// fen (0.01 yuan) so that sums never suffer from floating-point rounding.
+type Amount int64
+
+// ParseAmount parses an amount written in yuan as a decimal such as -1234.5.
+// Digits beyond the fen are rejected unless they are zero.
+func ParseAmount(s string) (Amount, error) {
+	str := strings.TrimSpace(s)
+	negative := false
+	switch {
+	case strings.HasPrefix(str, "-"):
+		negative, str = true, str[1:]
+	case strings.HasPrefix(str, "+"):
+		str = str[1:]
+	}
+
+	whole, frac := str, ""
+	if i := strings.IndexByte(str, '.'); i >= 0 {
+		whole, frac = str[:i], str[i+1:]
+	}
+	frac = strings.TrimRight(frac, "0")
+	if whole == "" && frac == "" || len(frac) > 2 || len(whole) > 16 || !isDigits(whole) || !isDigits(frac) {
+		return 0, fmt.Errorf("malformed amount %q", s)
+	}
+
+	frac += strings.Repeat("0", 2-len(frac))
+	fen, err := strconv.ParseInt(whole+frac, 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("malformed amount %q", s)
+	}
+	if negative {
+		fen = -fen
+	}
+	return Amount(fen), nil
+}
+
+func isDigits(s string) bool {
+	for _, c := range s {
+		if c < '0' || c > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+// Fen returns the amount in fen.
+func (a Amount) Fen() int64 {
+	return int64(a)
+}
+
+// String returns the amount in yuan with two decimals, such as -1234.50.
+func (a Amount) String() string {
+	sign := ""
+	fen := int64(a)
+	if fen < 0 {
+		sign, fen = "-", -fen
+	}
+	return fmt.Sprintf("%s%d.%02d", sign, fen/100, fen%100)
+}
+
+// MarshalJSON implements json.Marshaler. The amount is encoded as a string,
+// so that decoders do not turn it into a floating-point number.
+func (a Amount) MarshalJSON() ([]byte, error) {
+	return json.Marshal(a.String())
+}
+
+// UnmarshalJSON implements json.Unmarshaler. Both numbers and strings are
+// accepted, and parsed exactly.
+func (a *Amount) UnmarshalJSON(data []byte) error {
+	if bytes.Equal(data, []byte("null")) {
+		*a = 0
+		return nil
+	}
+
+	str := string(data)
+	if len(data) > 0 && data[0] == '"' {
+		if err := json.Unmarshal(data, &str); err != nil {
+			return err
+		}
+	}
+
+	amount, err := ParseAmount(str)
+	if err != nil {
+		return err
+	}
+	*a = amount
+	return nil
+}
+
+// SensitiveAmount is an Amount that is redacted whenever it is formatted or
+// encoded, so that it cannot leak into logs or JSON output by accident. Exact
+// returns the amount.
+type SensitiveAmount Amount
+
+// Exact returns the amount.
+func (a SensitiveAmount) Exact() Amount {
+	return Amount(a)
+}
+
+// String returns Redacted.
+func (a SensitiveAmount) String() string {
+	return Redacted
+}
+
+// Format implements fmt.Formatter. Every verb, including %d and %#v, formats
+// the amount as Redacted.
+func (a SensitiveAmount) Format(f fmt.State, verb rune) {
+	fmt.Fprint(f, Redacted)
+}
+
+// MarshalJSON implements json.Marshaler. The amount is encoded as Redacted;
+// encode Exact to keep it.
+func (a SensitiveAmount) MarshalJSON() ([]byte, error) {
+	return json.Marshal(Redacted)
+}
+
+// MarshalText implements encoding.TextMarshaler. The amount is encoded as
+// Redacted.
+func (a SensitiveAmount) MarshalText() ([]byte, error) {
+	return []byte(Redacted), nil
+}
+
+// UnmarshalJSON implements json.Unmarshaler, accepting the same values as
+// Amount.
+func (a *SensitiveAmount) UnmarshalJSON(data []byte) error {
+	return (*Amount)(a).UnmarshalJSON(data)
+}
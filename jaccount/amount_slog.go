@@ -0,0 +1,27 @@
+//go:build go1.21
+// +build go1.21
+
+/*
+Copyright 2021 The Go jAccount Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package jaccount
+
+import "log/slog"
+
+// LogValue implements slog.LogValuer. The amount is logged as Redacted.
+func (a SensitiveAmount) LogValue() slog.Value {
+	return slog.StringValue(Redacted)
+}
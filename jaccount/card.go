@@ -41,8 +41,14 @@ type CardInfo struct {
 
 // GetCardInfo returns the card information for the user.
 //
+// It requires the card_info scope.
+//
 // See https://developer.sjtu.edu.cn/api/card.html#%E8%8E%B7%E5%8F%96%E6%A0%A1%E5%9B%AD%E5%8D%A1%E4%BF%A1%E6%81%AF for more information.
 func (s *CardService) GetCardInfo(ctx context.Context) (*CardInfo, error) {
+	if err := s.client.checkScopes(ScopeCardInfo); err != nil {
+		return nil, err
+	}
+
 	req, err := s.client.NewRequest(http.MethodGet, "/v1/me/card", nil)
 	if err != nil {
 		return nil, err
@@ -74,8 +80,14 @@ type CardListTransactionsOptions struct {
 
 // ListTransactions returns a list of transactions for the given card.
 //
+// It requires the card_transactions scope.
+//
 // See https://developer.sjtu.edu.cn/api/card.html#%E8%8E%B7%E5%8F%96%E4%BA%A4%E6%98%93%E8%AE%B0%E5%BD%95%E4%BF%A1%E6%81%AF for more information.
 func (s *CardService) ListTransactions(ctx context.Context, opts *CardListTransactionsOptions) ([]*CardTransaction, error) {
+	if err := s.client.checkScopes(ScopeCardTransactions); err != nil {
+		return nil, err
+	}
+
 	values, err := query.Values(opts)
 	if err != nil {
 		return nil, err
@@ -0,0 +1,145 @@
+/*
+Copyright 2021 The Go jAccount Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package jaccount
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+
+	"github.com/google/go-querystring/query"
+)
+
+// ClassesService handles communications with the class related methods of
+// the jAccount API, for teachers.
+type ClassesService service
+
+// Class represents a class of a course taught by the user.
+type Class struct {
+	ClassCode  string      `json:"classCode,omitempty"`
+	CourseCode string      `json:"courseCode,omitempty"`
+	CourseName string      `json:"courseName,omitempty"`
+	Term       Term        `json:"term"`
+	Credits    float64     `json:"credits,omitempty"`
+	Teachers   []*Teacher  `json:"teachers,omitempty"`
+	Enrolled   int         `json:"enrolledCount,omitempty"`
+	Capacity   int         `json:"capacity,omitempty"`
+	Slots      []*TimeSlot `json:"schedules,omitempty"`
+}
+
+// Student represents a student enrolled in a class.
+type Student struct {
+	Account  string    `json:"account,omitempty"`
+	Name     string    `json:"name,omitempty"`
+	Code     string    `json:"code,omitempty"`
+	Major    *Major    `json:"major,omitempty"`
+	Organize *Organize `json:"organize,omitempty"`
+	ClassNO  string    `json:"classNo,omitempty"`
+}
+
+// ClassListOptions specifies the optional parameters to the
+// ClassesService.List method.
+type ClassListOptions struct {
+	// Term filters classes by term if set.
+	Term Term `url:"term,omitempty"`
+
+	ListOptions
+}
+
+// List returns a page of the classes taught by the user.
+//
+// It requires the classes scope.
+func (s *ClassesService) List(ctx context.Context, opts *ClassListOptions) ([]*Class, *Response, error) {
+	if err := s.client.checkScopes(ScopeClasses); err != nil {
+		return nil, nil, err
+	}
+
+	values, err := query.Values(opts)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	req, err := s.client.NewRequest(http.MethodGet, "/v1/me/classes", values)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var classes []*Class
+	response, _, err := s.client.do(ctx, req, &classes)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return classes, response, nil
+}
+
+// Get returns the class with the given code.
+//
+// It requires the classes scope.
+func (s *ClassesService) Get(ctx context.Context, code string) (*Class, error) {
+	if err := s.client.checkScopes(ScopeClasses); err != nil {
+		return nil, err
+	}
+
+	req, err := s.client.NewRequest(http.MethodGet, classPath(code), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	class := make([]Class, 1)
+	_, err = s.client.Do(ctx, req, &class)
+	if err != nil {
+		return nil, err
+	}
+	if len(class) == 0 {
+		return nil, ErrNoEntities
+	}
+
+	return &class[0], nil
+}
+
+// Roster returns a page of the students enrolled in the class.
+//
+// It requires the classes scope.
+func (s *ClassesService) Roster(ctx context.Context, code string, opts *ListOptions) ([]*Student, *Response, error) {
+	if err := s.client.checkScopes(ScopeClasses); err != nil {
+		return nil, nil, err
+	}
+
+	values, err := query.Values(opts)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	req, err := s.client.NewRequest(http.MethodGet, classPath(code)+"/students", values)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var students []*Student
+	response, _, err := s.client.do(ctx, req, &students)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return students, response, nil
+}
+
+func classPath(code string) string {
+	return fmt.Sprintf("/v1/classes/%s", url.PathEscape(code))
+}
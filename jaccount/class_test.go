@@ -0,0 +1,88 @@
+/*
+Copyright 2021 The Go jAccount Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package jaccount
+
+import (
+	"context"
+	"net/http"
+	"testing"
+)
+
+func TestClassesService(t *testing.T) {
+	client, mux, teardown := setup(t)
+	defer teardown()
+
+	class := &Class{
+		ClassCode:  "(2026-2027-1)-CS1501-01",
+		CourseCode: "CS1501",
+		CourseName: "程序设计",
+		Term:       Term{Year: 2026, Semester: Fall},
+		Enrolled:   2,
+	}
+	students := []*Student{
+		{Account: "alice", Name: "Alice", Code: "526030910001", Major: &Major{ID: "03", Name: "计算机科学与技术"}},
+		{Account: "bob", Name: "Bob", Code: "526030910002"},
+	}
+
+	mux.HandleFunc("/v1/me/classes", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, http.MethodGet)
+		if got := r.URL.Query().Get("term"); got != "2026-2027-1" {
+			t.Errorf("term = %q, want %q", got, "2026-2027-1")
+		}
+		writeResponse(t, w, []*Class{class}, 1, "")
+	})
+	mux.HandleFunc("/v1/classes/(2026-2027-1)-CS1501-01", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, http.MethodGet)
+		writeResponse(t, w, []*Class{class}, 0, "")
+	})
+	mux.HandleFunc("/v1/classes/(2026-2027-1)-CS1501-01/students", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, http.MethodGet)
+		if r.URL.Query().Get("nextToken") == "" {
+			writeResponse(t, w, students[:1], 2, "2")
+		} else {
+			writeResponse(t, w, students[1:], 2, "")
+		}
+	})
+
+	ctx := context.Background()
+	classes, _, err := client.Classes.List(ctx, &ClassListOptions{Term: class.Term})
+	if err != nil || len(classes) != 1 || classes[0].Enrolled != 2 {
+		t.Fatalf("ClassesService.List() = %v, %v", classes, err)
+	}
+
+	got, err := client.Classes.Get(ctx, class.ClassCode)
+	if err != nil || got.CourseCode != "CS1501" || got.Term != class.Term {
+		t.Errorf("ClassesService.Get() = %+v, %v", got, err)
+	}
+
+	var roster []*Student
+	opts := &ListOptions{}
+	for {
+		page, response, err := client.Classes.Roster(ctx, class.ClassCode, opts)
+		if err != nil {
+			t.Fatalf("ClassesService.Roster() error = %v", err)
+		}
+		roster = append(roster, page...)
+		if response.NextToken == "" {
+			break
+		}
+		opts.NextToken = response.NextToken
+	}
+	if len(roster) != 2 || roster[0].Major.Name != "计算机科学与技术" {
+		t.Errorf("ClassesService.Roster() = %v", roster)
+	}
+}
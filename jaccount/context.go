@@ -0,0 +1,66 @@
+/*
+Copyright 2021 The Go jAccount Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package jaccount
+
+import "context"
+
+type contextKey int
+
+const (
+	profileKey contextKey = iota
+	idTokenKey
+	tokenInfoKey
+)
+
+// ContextWithProfile returns a copy of ctx that carries the profile of the
+// authenticated user.
+func ContextWithProfile(ctx context.Context, profile *Profile) context.Context {
+	return context.WithValue(ctx, profileKey, profile)
+}
+
+// ProfileFromContext returns the profile of the authenticated user stored in
+// ctx, if any.
+func ProfileFromContext(ctx context.Context) (*Profile, bool) {
+	profile, ok := ctx.Value(profileKey).(*Profile)
+	return profile, ok && profile != nil
+}
+
+// ContextWithIDToken returns a copy of ctx that carries the ID token of the
+// authenticated user.
+func ContextWithIDToken(ctx context.Context, idToken *IDToken) context.Context {
+	return context.WithValue(ctx, idTokenKey, idToken)
+}
+
+// IDTokenFromContext returns the ID token of the authenticated user stored in
+// ctx, if any.
+func IDTokenFromContext(ctx context.Context) (*IDToken, bool) {
+	idToken, ok := ctx.Value(idTokenKey).(*IDToken)
+	return idToken, ok && idToken != nil
+}
+
+// ContextWithTokenInfo returns a copy of ctx that carries the introspected
+// access token of the request.
+func ContextWithTokenInfo(ctx context.Context, info *TokenInfo) context.Context {
+	return context.WithValue(ctx, tokenInfoKey, info)
+}
+
+// TokenInfoFromContext returns the introspected access token stored in ctx,
+// if any.
+func TokenInfoFromContext(ctx context.Context) (*TokenInfo, bool) {
+	info, ok := ctx.Value(tokenInfoKey).(*TokenInfo)
+	return info, ok && info != nil
+}
@@ -26,7 +26,15 @@ import (
 // See https://developer.sjtu.edu.cn/api/enterprise.html for more information.
 type EnterpriseService service
 
+// GetUserPositions gets the enterprise positions of the user.
+//
+// It requires the essential scope, since positions are part of the identity
+// data of the user.
 func (s *EnterpriseService) GetUserPositions(ctx context.Context) (*Positions, error) {
+	if err := s.client.checkScopes(ScopeEssential); err != nil {
+		return nil, err
+	}
+
 	req, err := s.client.NewRequest(http.MethodGet, "/v1/enterprise/user/positions", nil)
 	if err != nil {
 		return nil, err
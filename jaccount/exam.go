@@ -0,0 +1,125 @@
+/*
+Copyright 2021 The Go jAccount Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package jaccount
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sort"
+	"time"
+)
+
+// ExamsService handles communications with the exam related methods of the
+// jAccount API.
+type ExamsService service
+
+// Exam represents an exam the user takes.
+type Exam struct {
+	CourseCode string    `json:"courseCode,omitempty"`
+	CourseName string    `json:"courseName,omitempty"`
+	Name       string    `json:"name,omitempty"`
+	Type       string    `json:"examType,omitempty"`
+	Term       Term      `json:"term"`
+	Start      Timestamp `json:"startTime"`
+	End        Timestamp `json:"endTime"`
+	Location   string    `json:"location,omitempty"`
+	Campus     string    `json:"campus,omitempty"`
+	Seat       string    `json:"seatNo,omitempty"`
+}
+
+// Overlaps reports whether the time windows of the exams overlap.
+func (e *Exam) Overlaps(other *Exam) bool {
+	return e.Start.Before(other.End.Time) && other.Start.Before(e.End.Time)
+}
+
+// List returns the exams of the user in the term, in order of start time.
+//
+// It requires the exams scope.
+func (s *ExamsService) List(ctx context.Context, term Term) ([]*Exam, error) {
+	if err := s.client.checkScopes(ScopeExams); err != nil {
+		return nil, err
+	}
+	if term.IsZero() {
+		return nil, ErrNoTerm
+	}
+
+	path := fmt.Sprintf("/v1/me/exams/%s", url.PathEscape(term.String()))
+	req, err := s.client.NewRequest(http.MethodGet, path, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var exams []*Exam
+	_, err = s.client.Do(ctx, req, &exams)
+	if err != nil {
+		return nil, err
+	}
+
+	sortExams(exams)
+	return exams, nil
+}
+
+// UpcomingExams returns the exams that have not ended at now, in order of
+// start time. A positive within limits them to those starting in that period.
+func UpcomingExams(exams []*Exam, now time.Time, within time.Duration) []*Exam {
+	var upcoming []*Exam
+	for _, e := range exams {
+		if !e.End.After(now) {
+			continue
+		}
+		if within > 0 && e.Start.After(now.Add(within)) {
+			continue
+		}
+		upcoming = append(upcoming, e)
+	}
+	sortExams(upcoming)
+	return upcoming
+}
+
+// ExamConflict is a pair of exams whose time windows overlap, the first
+// starting no later than the second.
+type ExamConflict struct {
+	First  *Exam
+	Second *Exam
+}
+
+// ExamConflicts returns the pairs of exams whose time windows overlap.
+func ExamConflicts(exams []*Exam) []ExamConflict {
+	sorted := make([]*Exam, len(exams))
+	copy(sorted, exams)
+	sortExams(sorted)
+
+	var conflicts []ExamConflict
+	for i, e := range sorted {
+		for _, other := range sorted[i+1:] {
+			if !other.Start.Before(e.End.Time) {
+				// Later exams start even later.
+				break
+			}
+			conflicts = append(conflicts, ExamConflict{First: e, Second: other})
+		}
+	}
+	return conflicts
+}
+
+func sortExams(exams []*Exam) {
+	sort.SliceStable(exams, func(i, j int) bool {
+		return exams[i].Start.Before(exams[j].Start.Time)
+	})
+}
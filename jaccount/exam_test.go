@@ -0,0 +1,83 @@
+/*
+Copyright 2021 The Go jAccount Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package jaccount
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestExamsService_List(t *testing.T) {
+	client, mux, teardown := setup(t)
+	defer teardown()
+
+	mux.HandleFunc("/v1/me/exams/2026-2027-1", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, http.MethodGet)
+		fmt.Fprint(w, `{"errno":0,"error":"success","entities":[
+			{"courseCode":"MA1201","courseName":"高等数学","startTime":1799629200000,"endTime":1799636400000,"location":"东上院301","seatNo":"23"},
+			{"courseCode":"CS1501","courseName":"程序设计","startTime":"2027-01-08T09:00:00+08:00","endTime":"2027-01-08T11:00:00+08:00","location":"东中院3-101","seatNo":"7"}
+		]}`)
+	})
+
+	exams, err := client.Exams.List(context.Background(), Term{Year: 2026, Semester: Fall})
+	if err != nil {
+		t.Fatalf("ExamsService.List() error = %v", err)
+	}
+	if len(exams) != 2 || exams[0].CourseCode != "CS1501" || exams[1].Seat != "23" {
+		t.Fatalf("ExamsService.List() = %+v, want exams in order of start time", exams)
+	}
+	if want := time.Date(2027, 1, 11, 9, 0, 0, 0, time.FixedZone("CST", 8*60*60)); !exams[1].Start.Equal(want) {
+		t.Errorf("ExamsService.List() start = %v, want %v", exams[1].Start, want)
+	}
+}
+
+func TestExamsService_ListNoTerm(t *testing.T) {
+	client := NewClient(nil)
+	if _, err := client.Exams.List(context.Background(), Term{}); !errors.Is(err, ErrNoTerm) {
+		t.Errorf("ExamsService.List() error = %v, want %v", err, ErrNoTerm)
+	}
+}
+
+func TestExamHelpers(t *testing.T) {
+	day := time.Date(2027, 1, 8, 0, 0, 0, 0, time.UTC)
+	exam := func(code string, start, end int) *Exam {
+		return &Exam{
+			CourseCode: code,
+			Start:      Timestamp{day.Add(time.Duration(start) * time.Hour)},
+			End:        Timestamp{day.Add(time.Duration(end) * time.Hour)},
+		}
+	}
+	exams := []*Exam{exam("C", 13, 15), exam("A", 9, 11), exam("B", 10, 12), exam("D", 15, 17), exam("E", 40, 42)}
+
+	conflicts := ExamConflicts(exams)
+	if len(conflicts) != 1 || conflicts[0].First.CourseCode != "A" || conflicts[0].Second.CourseCode != "B" {
+		t.Errorf("ExamConflicts() = %+v, want A and B", conflicts)
+	}
+
+	upcoming := UpcomingExams(exams, day.Add(11*time.Hour+30*time.Minute), 24*time.Hour)
+	var codes string
+	for _, e := range upcoming {
+		codes += e.CourseCode
+	}
+	if codes != "BCD" {
+		t.Errorf("UpcomingExams() = %s, want BCD", codes)
+	}
+}
@@ -0,0 +1,205 @@
+/*
+Copyright 2021 The Go jAccount Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package jaccount
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sort"
+	"time"
+
+	"github.com/google/go-querystring/query"
+)
+
+// IncomeService handles communications with the salary and income related
+// methods of the jAccount API, for faculty and staff.
+//
+// Income records are sensitive. Their amounts are SensitiveAmounts, which are
+// redacted when formatted, logged or encoded as JSON, so that records can be
+// logged safely; call Exact to get an amount.
+type IncomeService service
+
+// Redacted replaces sensitive values when they are formatted or encoded.
+const Redacted = "[REDACTED]"
+
+// Month is a calendar month, written as 2026-09.
+type Month struct {
+	Year  int
+	Month time.Month
+}
+
+// ParseMonth parses a month written as 2026-09.
+func ParseMonth(s string) (Month, error) {
+	t, err := time.Parse("2006-01", s)
+	if err != nil {
+		return Month{}, fmt.Errorf("malformed month %q", s)
+	}
+	return Month{t.Year(), t.Month()}, nil
+}
+
+// IsZero reports whether m is the zero Month.
+func (m Month) IsZero() bool {
+	return m == Month{}
+}
+
+// String returns the month written as 2026-09.
+func (m Month) String() string {
+	return fmt.Sprintf("%04d-%02d", m.Year, m.Month)
+}
+
+// EncodeValues implements query.Encoder. The zero Month is omitted.
+func (m Month) EncodeValues(key string, v *url.Values) error {
+	if !m.IsZero() {
+		v.Set(key, m.String())
+	}
+	return nil
+}
+
+// MarshalText implements encoding.TextMarshaler.
+func (m Month) MarshalText() ([]byte, error) {
+	return []byte(m.String()), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler.
+func (m *Month) UnmarshalText(data []byte) error {
+	month, err := ParseMonth(string(data))
+	if err != nil {
+		return err
+	}
+	*m = month
+	return nil
+}
+
+// IncomeRecord represents a payment to the user, such as a monthly salary or
+// a bonus.
+type IncomeRecord struct {
+	ID          string          `json:"id,omitempty"`
+	Month       Month           `json:"month"`
+	Category    string          `json:"category,omitempty"`
+	Description string          `json:"description,omitempty"`
+	Gross       SensitiveAmount `json:"gross"`
+	Deductions  SensitiveAmount `json:"deductions"`
+	Net         SensitiveAmount `json:"net"`
+	PaidAt      *Timestamp      `json:"payTime,omitempty"`
+}
+
+// String returns the record with its amounts redacted.
+func (r IncomeRecord) String() string {
+	return fmt.Sprintf("IncomeRecord{ID: %q, Month: %s, Category: %q, Gross: %s, Deductions: %s, Net: %s}",
+		r.ID, r.Month, r.Category, Redacted, Redacted, Redacted)
+}
+
+// GoString returns the record with its amounts redacted, for the %#v verb.
+func (r IncomeRecord) GoString() string {
+	return "jaccount." + r.String()
+}
+
+// IncomeListOptions specifies the optional parameters to the
+// IncomeService.List method.
+type IncomeListOptions struct {
+	// From and To filter records by month, inclusive.
+	From Month `url:"beginMonth,omitempty"`
+	To   Month `url:"endMonth,omitempty"`
+
+	// Category filters records by category if set.
+	Category string `url:"category,omitempty"`
+
+	ListOptions
+}
+
+// List returns a page of the income records of the user.
+//
+// It requires the income scope.
+func (s *IncomeService) List(ctx context.Context, opts *IncomeListOptions) ([]*IncomeRecord, *Response, error) {
+	if err := s.client.checkScopes(ScopeIncome); err != nil {
+		return nil, nil, err
+	}
+
+	values, err := query.Values(opts)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	req, err := s.client.NewRequest(http.MethodGet, "/v1/me/income", values)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var records []*IncomeRecord
+	response, _, err := s.client.do(ctx, req, &records)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return records, response, nil
+}
+
+// IncomeSummary totals the income records of a category in a year.
+type IncomeSummary struct {
+	Year       int             `json:"year"`
+	Category   string          `json:"category"`
+	Records    int             `json:"records"`
+	Gross      SensitiveAmount `json:"gross"`
+	Deductions SensitiveAmount `json:"deductions"`
+	Net        SensitiveAmount `json:"net"`
+}
+
+// String returns the summary with its amounts redacted.
+func (s IncomeSummary) String() string {
+	return fmt.Sprintf("IncomeSummary{Year: %d, Category: %q, Records: %d, Gross: %s, Deductions: %s, Net: %s}",
+		s.Year, s.Category, s.Records, Redacted, Redacted, Redacted)
+}
+
+// GoString returns the summary with its amounts redacted, for the %#v verb.
+func (s IncomeSummary) GoString() string {
+	return "jaccount." + s.String()
+}
+
+// SummarizeIncome totals the records per year and category, in order of year
+// and category.
+func SummarizeIncome(records []*IncomeRecord) []*IncomeSummary {
+	type key struct {
+		year     int
+		category string
+	}
+	totals := make(map[key]*IncomeSummary)
+
+	var summaries []*IncomeSummary
+	for _, r := range records {
+		k := key{r.Month.Year, r.Category}
+		s, ok := totals[k]
+		if !ok {
+			s = &IncomeSummary{Year: k.year, Category: k.category}
+			totals[k] = s
+			summaries = append(summaries, s)
+		}
+		s.Records++
+		s.Gross += r.Gross
+		s.Deductions += r.Deductions
+		s.Net += r.Net
+	}
+
+	sort.Slice(summaries, func(i, j int) bool {
+		if summaries[i].Year != summaries[j].Year {
+			return summaries[i].Year < summaries[j].Year
+		}
+		return summaries[i].Category < summaries[j].Category
+	})
+	return summaries
+}
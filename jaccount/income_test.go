@@ -0,0 +1,178 @@
+/*
+Copyright 2021 The Go jAccount Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package jaccount
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseAmount(t *testing.T) {
+	tests := []struct {
+		in   string
+		want Amount
+		err  bool
+	}{
+		{in: "0", want: 0},
+		{in: "12345.67", want: 1234567},
+		{in: "-0.5", want: -50},
+		{in: "+.05", want: 5},
+		{in: "100.000", want: 10000},
+		{in: "0.001", err: true},
+		{in: "1,000.00", err: true},
+		{in: "1e3", err: true},
+		{in: "", err: true},
+		{in: "-", err: true},
+	}
+	for _, tt := range tests {
+		got, err := ParseAmount(tt.in)
+		if (err != nil) != tt.err || got != tt.want {
+			t.Errorf("ParseAmount(%q) = %v, %v, want %v, error %v", tt.in, got, err, tt.want, tt.err)
+		}
+	}
+}
+
+func TestAmount_JSON(t *testing.T) {
+	var r struct {
+		A, B, C Amount
+	}
+	if err := json.Unmarshal([]byte(`{"A": 0.1, "B": "0.2", "C": null}`), &r); err != nil {
+		t.Fatalf("json.Unmarshal() error = %v", err)
+	}
+	if sum := r.A + r.B; sum.String() != "0.30" {
+		t.Errorf("0.1 + 0.2 = %v, want 0.30", sum)
+	}
+
+	data, err := json.Marshal(Amount(-123450))
+	if err != nil {
+		t.Fatalf("json.Marshal() error = %v", err)
+	}
+	if string(data) != `"-1234.50"` {
+		t.Errorf("json.Marshal() = %s, want %q", data, "-1234.50")
+	}
+}
+
+func TestIncomeService_List(t *testing.T) {
+	client, mux, teardown := setup(t)
+	defer teardown()
+
+	mux.HandleFunc("/v1/me/income", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, http.MethodGet)
+		q := r.URL.Query()
+		if got := q.Get("beginMonth"); got != "2026-01" {
+			t.Errorf("beginMonth = %q, want %q", got, "2026-01")
+		}
+		if got := q.Get("endMonth"); got != "" {
+			t.Errorf("endMonth = %q, want empty", got)
+		}
+		if got := q.Get("category"); got != "salary" {
+			t.Errorf("category = %q, want %q", got, "salary")
+		}
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, `{"errno": 0, "total": 1, "entities": [
+			{"id": "r1", "month": "2026-01", "category": "salary", "gross": 12000.5, "deductions": "2000.25", "net": "10000.25"}
+		]}`)
+	})
+
+	records, _, err := client.Income.List(context.Background(), &IncomeListOptions{
+		From:     Month{2026, time.January},
+		Category: "salary",
+	})
+	if err != nil {
+		t.Fatalf("IncomeService.List() error = %v", err)
+	}
+	if len(records) != 1 {
+		t.Fatalf("IncomeService.List() returned %d records, want 1", len(records))
+	}
+	r := records[0]
+	if r.Month != (Month{2026, time.January}) || r.Gross != 1200050 || r.Deductions != 200025 || r.Net != 1000025 {
+		t.Errorf("IncomeService.List() = %#v, gross %v, deductions %v, net %v", r, r.Gross.Exact(), r.Deductions.Exact(), r.Net.Exact())
+	}
+}
+
+func TestSummarizeIncome(t *testing.T) {
+	records := []*IncomeRecord{
+		{Month: Month{2026, time.February}, Category: "salary", Gross: 1000010, Deductions: 10, Net: 1000000},
+		{Month: Month{2025, time.December}, Category: "bonus", Gross: 500000, Net: 450000, Deductions: 50000},
+		{Month: Month{2026, time.January}, Category: "salary", Gross: 1000020, Deductions: 20, Net: 1000000},
+		{Month: Month{2026, time.January}, Category: "bonus", Gross: 1, Net: 1},
+	}
+
+	got := SummarizeIncome(records)
+	want := []IncomeSummary{
+		{Year: 2025, Category: "bonus", Records: 1, Gross: 500000, Deductions: 50000, Net: 450000},
+		{Year: 2026, Category: "bonus", Records: 1, Gross: 1, Net: 1},
+		{Year: 2026, Category: "salary", Records: 2, Gross: 2000030, Deductions: 30, Net: 2000000},
+	}
+	if len(got) != len(want) {
+		t.Fatalf("SummarizeIncome() returned %d summaries, want %d", len(got), len(want))
+	}
+	for i := range want {
+		if *got[i] != want[i] {
+			t.Errorf("SummarizeIncome()[%d] = %+v, want %+v", i, *got[i], want[i])
+		}
+	}
+}
+
+func TestIncome_Redacted(t *testing.T) {
+	r := &IncomeRecord{ID: "r1", Month: Month{2026, time.March}, Category: "salary", Gross: 1234567, Deductions: 234567, Net: 1000000}
+	s := SummarizeIncome([]*IncomeRecord{r})[0]
+
+	for _, v := range []interface{}{r, *r, s, []*IncomeRecord{r}, r.Net, r.Gross} {
+		for _, verb := range []string{"%v", "%+v", "%#v", "%s"} {
+			out := fmt.Sprintf(verb, v)
+			for _, amount := range []string{"12345.67", "2345.67", "10000.00", "1234567", "234567", "1000000", "2345.67"} {
+				if strings.Contains(out, amount) {
+					t.Errorf("Sprintf(%q) = %q, contains %s", verb, out, amount)
+				}
+			}
+			if !strings.Contains(out, Redacted) {
+				t.Errorf("Sprintf(%q) = %q, not redacted", verb, out)
+			}
+		}
+	}
+
+	// Integer verbs do not use String, but amounts implement fmt.Formatter.
+	for _, v := range []interface{}{*r, r.Net} {
+		if out := fmt.Sprintf("%d", v); strings.Contains(out, "1000000") || !strings.Contains(out, Redacted) {
+			t.Errorf("Sprintf(%q) = %q, not redacted", "%d", out)
+		}
+	}
+}
+
+func TestIncome_RedactedJSON(t *testing.T) {
+	r := &IncomeRecord{ID: "r1", Month: Month{2026, time.March}, Gross: 1234567, Deductions: 234567, Net: 1000000}
+
+	data, err := json.Marshal([]*IncomeRecord{r})
+	if err != nil {
+		t.Fatalf("json.Marshal() error = %v", err)
+	}
+	for _, amount := range []string{"12345.67", "2345.67", "10000.00", "1234567", "1000000"} {
+		if strings.Contains(string(data), amount) {
+			t.Errorf("json.Marshal() = %s, contains %s", data, amount)
+		}
+	}
+
+	if got := r.Net.Exact(); got != 1000000 || got.String() != "10000.00" {
+		t.Errorf("SensitiveAmount.Exact() = %v, want 10000.00", got)
+	}
+}
@@ -0,0 +1,137 @@
+/*
+Copyright 2021 The Go jAccount Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package jaccount
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/google/go-querystring/query"
+)
+
+// TokenService handles communications with the OAuth 2.0 token introspection
+// and revocation endpoints of jAccount.
+//
+// See RFC 7662 and RFC 7009 for more information.
+type TokenService service
+
+// TokenTypeHint is a hint about the type of the token submitted for
+// introspection or revocation.
+type TokenTypeHint string
+
+const (
+	// AccessTokenHint indicates that the token is an access token.
+	AccessTokenHint TokenTypeHint = "access_token"
+	// RefreshTokenHint indicates that the token is a refresh token.
+	RefreshTokenHint TokenTypeHint = "refresh_token"
+)
+
+// TokenOptions specifies the optional parameters to the TokenService methods.
+type TokenOptions struct {
+	TokenTypeHint TokenTypeHint `url:"token_type_hint,omitempty"`
+
+	// ClientID and ClientSecret authenticate the client in the request body,
+	// which is needed when the HTTP client of Client does not carry any
+	// credentials itself.
+	ClientID     string `url:"client_id,omitempty"`
+	ClientSecret string `url:"client_secret,omitempty"`
+}
+
+// TokenInfo represents the meta information of a token.
+type TokenInfo struct {
+	Active    bool   `json:"active"`
+	Scope     string `json:"scope,omitempty"`
+	ClientID  string `json:"client_id,omitempty"`
+	Username  string `json:"username,omitempty"`
+	TokenType string `json:"token_type,omitempty"`
+	Expiry    int64  `json:"exp,omitempty"`
+	IssuedAt  int64  `json:"iat,omitempty"`
+	Subject   string `json:"sub,omitempty"`
+	Audience  string `json:"aud,omitempty"`
+	Issuer    string `json:"iss,omitempty"`
+}
+
+// Scopes returns the scopes associated with the token.
+func (t *TokenInfo) Scopes() []string {
+	return strings.Fields(t.Scope)
+}
+
+// ExpiresAt returns the expiry of the token, or the zero time if the token
+// does not expire.
+func (t *TokenInfo) ExpiresAt() time.Time {
+	if t.Expiry == 0 {
+		return time.Time{}
+	}
+	return time.Unix(t.Expiry, 0)
+}
+
+// Introspect returns the meta information of the given token. An inactive,
+// expired or unknown token is not an error, it is reported by Active being
+// false.
+//
+// It requires the introspect scope unless opts carries client credentials.
+func (s *TokenService) Introspect(ctx context.Context, token string, opts *TokenOptions) (*TokenInfo, error) {
+	// The client authenticates with its own access token unless client
+	// credentials are given in the body.
+	if opts == nil || opts.ClientID == "" {
+		if err := s.client.checkScopes(ScopeIntrospect); err != nil {
+			return nil, err
+		}
+	}
+
+	req, err := s.newRequest("introspect", token, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	info := new(TokenInfo)
+	_, err = s.client.doRaw(ctx, req, info)
+	if err != nil {
+		return nil, err
+	}
+
+	return info, nil
+}
+
+// Revoke revokes the given access token or refresh token. Revoking a refresh
+// token also invalidates the access tokens issued with it.
+func (s *TokenService) Revoke(ctx context.Context, token string, opts *TokenOptions) error {
+	req, err := s.newRequest("revoke", token, opts)
+	if err != nil {
+		return err
+	}
+
+	_, err = s.client.doRaw(ctx, req, nil)
+	return err
+}
+
+func (s *TokenService) newRequest(endpoint string, token string, opts *TokenOptions) (*http.Request, error) {
+	form, err := query.Values(opts)
+	if err != nil {
+		return nil, err
+	}
+	form.Set("token", token)
+
+	u, err := s.client.AuthBaseURL.Parse(endpoint)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.client.NewFormRequest(http.MethodPost, u.String(), form)
+}
@@ -0,0 +1,145 @@
+/*
+Copyright 2021 The Go jAccount Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package jaccount
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func newTokenTestClient(t *testing.T, handler http.HandlerFunc) (*Client, func()) {
+	ts := httptest.NewServer(handler)
+
+	testURL, err := url.Parse(ts.URL + "/oauth2/")
+	if err != nil {
+		t.Errorf("error = %v", err)
+	}
+
+	client := NewClient(nil)
+	client.AuthBaseURL = testURL
+
+	return client, ts.Close
+}
+
+func TestTokenService_Introspect(t *testing.T) {
+	client, teardown := newTokenTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.URL.Path != "/oauth2/introspect" {
+			http.NotFound(w, r)
+			return
+		}
+
+		switch r.PostFormValue("token") {
+		case "active":
+			if r.PostFormValue("token_type_hint") != "access_token" {
+				w.WriteHeader(http.StatusBadRequest)
+				w.Write([]byte(`{"error":"invalid_request"}`))
+				return
+			}
+			w.Write([]byte(`{"active":true,"scope":"basic essential","client_id":"client","sub":"user","exp":1700000000}`))
+		case "invalid_client":
+			w.WriteHeader(http.StatusUnauthorized)
+			w.Write([]byte(`{"error":"invalid_client"}`))
+		default:
+			w.Write([]byte(`{"active":false}`))
+		}
+	})
+	defer teardown()
+
+	tests := []struct {
+		name    string
+		token   string
+		want    *TokenInfo
+		wantErr bool
+	}{
+		{"active", "active", &TokenInfo{Active: true, Scope: "basic essential", ClientID: "client", Subject: "user", Expiry: 1700000000}, false},
+		{"inactive", "inactive", &TokenInfo{Active: false}, false},
+		{"invalid client", "invalid_client", nil, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := client.Token.Introspect(context.Background(), tt.token, &TokenOptions{TokenTypeHint: AccessTokenHint})
+			if (err != nil) != tt.wantErr {
+				t.Errorf("TokenService.Introspect() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("TokenService.Introspect() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTokenService_Revoke(t *testing.T) {
+	revoked := make(map[string]string)
+	client, teardown := newTokenTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.URL.Path != "/oauth2/revoke" {
+			http.NotFound(w, r)
+			return
+		}
+
+		if r.PostFormValue("client_id") != "client" || r.PostFormValue("client_secret") != "secret" {
+			w.WriteHeader(http.StatusUnauthorized)
+			w.Write([]byte(`{"error":"invalid_client"}`))
+			return
+		}
+		revoked[r.PostFormValue("token")] = r.PostFormValue("token_type_hint")
+	})
+	defer teardown()
+
+	tests := []struct {
+		name    string
+		token   string
+		opts    *TokenOptions
+		wantErr bool
+	}{
+		{"access token", "access", &TokenOptions{TokenTypeHint: AccessTokenHint, ClientID: "client", ClientSecret: "secret"}, false},
+		{"refresh token", "refresh", &TokenOptions{TokenTypeHint: RefreshTokenHint, ClientID: "client", ClientSecret: "secret"}, false},
+		{"unauthenticated", "access", nil, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := client.Token.Revoke(context.Background(), tt.token, tt.opts)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("TokenService.Revoke() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !tt.wantErr && revoked[tt.token] != string(tt.opts.TokenTypeHint) {
+				t.Errorf("TokenService.Revoke() revoked %q with hint %q, want %q", tt.token, revoked[tt.token], tt.opts.TokenTypeHint)
+			}
+		})
+	}
+}
+
+func TestErrorResponse_Description(t *testing.T) {
+	client, teardown := newTokenTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusUnauthorized)
+		w.Write([]byte(`{"error":"invalid_client","error_description":"unknown client"}`))
+	})
+	defer teardown()
+
+	_, err := client.Token.Introspect(context.Background(), "token", nil)
+	if err == nil || !strings.HasSuffix(err.Error(), "401 invalid_client: unknown client") {
+		t.Errorf("TokenService.Introspect() error = %v, want the error description", err)
+	}
+}
@@ -17,32 +17,63 @@ limitations under the License.
 package jaccount
 
 import (
+	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
 	"io/ioutil"
 	"net/http"
 	"net/url"
+	"strings"
+
+	"golang.org/x/oauth2"
 )
 
 const (
-	defaultBaseURL = "https://api.sjtu.edu.cn"
-	userAgent      = "go-jaccount"
+	defaultBaseURL     = "https://api.sjtu.edu.cn"
+	defaultAuthBaseURL = "https://jaccount.sjtu.edu.cn/oauth2/"
+	userAgent          = "go-jaccount"
 )
 
+// ErrNoEntities is returned by methods that return a single entity when the
+// response holds none.
+var ErrNoEntities = errors.New("response has no entities")
+
 // Client manages communication with the jAccount API.
 type Client struct {
 	client *http.Client
 
 	BaseURL *url.URL
 
+	// AuthBaseURL is the base URL of the OAuth 2.0 endpoints, such as token
+	// introspection and revocation.
+	AuthBaseURL *url.URL
+
 	UserAgent string
 
+	// TokenSource, if set, is used to look up the scopes granted to the
+	// client, so that methods fail fast with an InsufficientScopeError.
+	// NewClient sets it when httpClient uses an oauth2.Transport.
+	TokenSource oauth2.TokenSource
+
 	common service
 
-	Profile    *ProfileService
-	Card       *CardService
-	Enterprise *EnterpriseService
+	Profile       *ProfileService
+	Card          *CardService
+	Enterprise    *EnterpriseService
+	Token         *TokenService
+	Notifications *NotificationService
+	Tasks         *TaskService
+	Mail          *MailService
+	Storage       *StorageService
+	Lessons       *LessonsService
+	Classes       *ClassesService
+	Exams         *ExamsService
+	Scores        *ScoresService
+	StudentList   *StudentListService
+	Income        *IncomeService
 }
 
 type service struct {
@@ -56,17 +87,34 @@ func NewClient(httpClient *http.Client) *Client {
 	}
 
 	baseURL, _ := url.Parse(defaultBaseURL)
+	authBaseURL, _ := url.Parse(defaultAuthBaseURL)
 
 	c := &Client{
-		client:    httpClient,
-		BaseURL:   baseURL,
-		UserAgent: userAgent,
+		client:      httpClient,
+		BaseURL:     baseURL,
+		AuthBaseURL: authBaseURL,
+		UserAgent:   userAgent,
 	}
 	c.common.client = c
 
+	if t, ok := httpClient.Transport.(*oauth2.Transport); ok {
+		c.TokenSource = t.Source
+	}
+
 	c.Profile = (*ProfileService)(&c.common)
 	c.Card = (*CardService)(&c.common)
 	c.Enterprise = (*EnterpriseService)(&c.common)
+	c.Token = (*TokenService)(&c.common)
+	c.Notifications = (*NotificationService)(&c.common)
+	c.Tasks = (*TaskService)(&c.common)
+	c.Mail = (*MailService)(&c.common)
+	c.Storage = (*StorageService)(&c.common)
+	c.Lessons = (*LessonsService)(&c.common)
+	c.Classes = (*ClassesService)(&c.common)
+	c.Exams = (*ExamsService)(&c.common)
+	c.Scores = (*ScoresService)(&c.common)
+	c.StudentList = (*StudentListService)(&c.common)
+	c.Income = (*IncomeService)(&c.common)
 
 	return c
 }
@@ -94,6 +142,83 @@ func (c *Client) NewRequest(method string, path string, queries url.Values) (*ht
 	return req, nil
 }
 
+// NewFormRequest creates an API request with a URL-encoded form body.
+func (c *Client) NewFormRequest(method string, path string, form url.Values) (*http.Request, error) {
+	url, err := c.BaseURL.Parse(path)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequest(method, url.String(), strings.NewReader(form.Encode()))
+	if err != nil {
+		return nil, err
+	}
+
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	if c.UserAgent != "" {
+		req.Header.Set("User-Agent", c.UserAgent)
+	}
+
+	return req, nil
+}
+
+// NewJSONRequest creates an API request with body encoded as JSON.
+func (c *Client) NewJSONRequest(method string, path string, body interface{}) (*http.Request, error) {
+	url, err := c.BaseURL.Parse(path)
+	if err != nil {
+		return nil, err
+	}
+
+	buf := new(bytes.Buffer)
+	err = json.NewEncoder(buf).Encode(body)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequest(method, url.String(), buf)
+	if err != nil {
+		return nil, err
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+
+	if c.UserAgent != "" {
+		req.Header.Set("User-Agent", c.UserAgent)
+	}
+
+	return req, nil
+}
+
+// NewUploadRequest creates an API request whose body is read from body as it
+// is sent, so that large uploads are not buffered in memory. A negative size
+// means that the length of body is unknown.
+func (c *Client) NewUploadRequest(method string, path string, body io.Reader, size int64, contentType string) (*http.Request, error) {
+	url, err := c.BaseURL.Parse(path)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequest(method, url.String(), body)
+	if err != nil {
+		return nil, err
+	}
+
+	if size >= 0 {
+		req.ContentLength = size
+	} else {
+		req.ContentLength = -1
+	}
+
+	req.Header.Set("Content-Type", contentType)
+
+	if c.UserAgent != "" {
+		req.Header.Set("User-Agent", c.UserAgent)
+	}
+
+	return req, nil
+}
+
 // Response is a jAccount API response.
 type Response struct {
 	ErrNO     int             `json:"errno,omitempty"`
@@ -103,37 +228,120 @@ type Response struct {
 	Entities  json.RawMessage `json:"entities,omitempty"`
 }
 
-// Do sends an API request and returns the API response.
+// Do sends an API request and returns the API response. The entities of the
+// response are decoded into v, unless v is nil.
 func (c *Client) Do(ctx context.Context, req *http.Request, v interface{}) (*http.Response, error) {
+	_, resp, err := c.do(ctx, req, v)
+	return resp, err
+}
+
+// do is like Do, but also returns the decoded Response, which carries the
+// pagination of list methods.
+func (c *Client) do(ctx context.Context, req *http.Request, v interface{}) (*Response, *http.Response, error) {
 	req = req.WithContext(ctx)
 	resp, err := c.client.Do(req)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 	defer resp.Body.Close()
 
 	body, err := ioutil.ReadAll(resp.Body)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 
 	response := new(Response)
 	err = json.Unmarshal(body, &response)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 
 	if resp.StatusCode != http.StatusOK || response.ErrNO != 0 {
 		errResp := &ErrorResponse{Response: resp}
 		err = json.Unmarshal(body, &errResp)
 		if err != nil {
-			return nil, err
+			return nil, nil, err
 		}
 
-		return nil, errResp
+		return nil, nil, errResp
+	}
+
+	if v == nil {
+		return response, resp, nil
 	}
 
 	err = json.Unmarshal(response.Entities, v)
+	if err != nil {
+		return response, resp, err
+	}
+
+	return response, resp, nil
+}
+
+// doStream sends an API request and returns the body of a successful response
+// unread, for downloads that should not be buffered in memory. The caller must
+// close the body. Errors are reported in a Response as usual.
+func (c *Client) doStream(ctx context.Context, req *http.Request) (io.ReadCloser, *http.Response, error) {
+	req = req.WithContext(ctx)
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if resp.StatusCode == http.StatusOK && !strings.HasPrefix(resp.Header.Get("Content-Type"), "application/json") {
+		return resp.Body, resp, nil
+	}
+	defer resp.Body.Close()
+
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	errResp := &ErrorResponse{Response: resp}
+	err = json.Unmarshal(body, &errResp)
+	if err != nil {
+		return nil, nil, err
+	}
+	if resp.StatusCode == http.StatusOK && errResp.ErrNO == 0 {
+		// A JSON document that is not an error is the download itself.
+		return ioutil.NopCloser(bytes.NewReader(body)), resp, nil
+	}
+
+	return nil, nil, errResp
+}
+
+// doRaw sends a request to an OAuth 2.0 endpoint and decodes the response
+// body into v. Unlike Do, the body is not wrapped in a Response.
+func (c *Client) doRaw(ctx context.Context, req *http.Request, v interface{}) (*http.Response, error) {
+	req = req.WithContext(ctx)
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		errResp := &ErrorResponse{Response: resp}
+		if len(body) > 0 {
+			// The error body is informational only, so a malformed one
+			// should not hide the status code.
+			_ = json.Unmarshal(body, &errResp)
+		}
+
+		return nil, errResp
+	}
+
+	if v == nil || len(body) == 0 {
+		return resp, nil
+	}
+
+	err = json.Unmarshal(body, v)
 	if err != nil {
 		return resp, err
 	}
@@ -147,15 +355,20 @@ type ErrorResponse struct {
 
 	ErrNO         int    `json:"errno,omitempty"`
 	InternalError string `json:"error,omitempty"`
+	Description   string `json:"error_description,omitempty"`
 	Total         int    `json:"total,omitempty"`
 }
 
 func (r *ErrorResponse) Error() string {
-	return fmt.Sprintf(
+	msg := fmt.Sprintf(
 		"%v %v: %d %v",
 		r.Response.Request.Method,
 		r.Response.Request.URL,
 		r.Response.StatusCode,
 		r.InternalError,
 	)
+	if r.Description != "" {
+		msg += ": " + r.Description
+	}
+	return msg
 }
@@ -15,3 +15,91 @@ limitations under the License.
 */
 
 package jaccount
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+)
+
+// setup sets up a test HTTP server along with a Client that is configured to
+// talk to that test server. Tests should register handlers on mux which
+// provide mock responses for the API method being tested.
+func setup(t *testing.T) (client *Client, mux *http.ServeMux, teardown func()) {
+	mux = http.NewServeMux()
+	ts := httptest.NewServer(mux)
+
+	testURL, err := url.Parse(ts.URL)
+	if err != nil {
+		t.Errorf("error = %v", err)
+	}
+
+	client = NewClient(nil)
+	client.BaseURL = testURL
+
+	return client, mux, ts.Close
+}
+
+// writeResponse writes a successful Response carrying the entities.
+func writeResponse(t *testing.T, w http.ResponseWriter, entities interface{}, total int, nextToken string) {
+	rawEntities, err := json.Marshal(entities)
+	if err != nil {
+		t.Errorf("error = %v", err)
+	}
+
+	err = json.NewEncoder(w).Encode(&Response{
+		Error:     "success",
+		Total:     total,
+		NextToken: nextToken,
+		Entities:  rawEntities,
+	})
+	if err != nil {
+		t.Errorf("error = %v", err)
+	}
+}
+
+func testMethod(t *testing.T, r *http.Request, want string) {
+	if got := r.Method; got != want {
+		t.Errorf("Request method: %v, want %v", got, want)
+	}
+}
+
+func TestTimestamp(t *testing.T) {
+	want := time.Date(2021, 5, 1, 23, 3, 25, 0, time.UTC)
+
+	tests := []struct {
+		name    string
+		data    string
+		want    time.Time
+		wantErr bool
+	}{
+		{"milliseconds", "1619910205000", want, false},
+		{"string", `"1619910205000"`, want, false},
+		{"RFC 3339", `"2021-05-01T23:03:25Z"`, want, false},
+		{"null", "null", time.Time{}, false},
+		{"zero", "0", time.Time{}, false},
+		{"invalid", `"yesterday"`, time.Time{}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got Timestamp
+			err := json.Unmarshal([]byte(tt.data), &got)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Timestamp.UnmarshalJSON() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !got.Equal(tt.want) {
+				t.Errorf("Timestamp.UnmarshalJSON() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	data, err := json.Marshal(NewTimestamp(want))
+	if err != nil || string(data) != "1619910205000" {
+		t.Errorf("Timestamp.MarshalJSON() = %s, %v, want %s", data, err, "1619910205000")
+	}
+}
@@ -0,0 +1,177 @@
+/*
+Copyright 2021 The Go jAccount Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package jaccount
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"sync"
+	"time"
+
+	"gopkg.in/square/go-jose.v2"
+	"gopkg.in/square/go-jose.v2/jwt"
+)
+
+// KeySet provides the keys that ID tokens may be signed with.
+type KeySet interface {
+	// Keys returns the candidate keys for a token signed with the given key
+	// ID, which is empty if the token names no key.
+	Keys(ctx context.Context, keyID string) ([]jose.JSONWebKey, error)
+}
+
+// StaticKeySet is a fixed set of keys. For ID tokens signed with HS256, it
+// holds the client secret:
+//
+//	jaccount.StaticKeySet{{Key: []byte(clientSecret), Algorithm: "HS256"}}
+type StaticKeySet []jose.JSONWebKey
+
+// Keys implements KeySet.
+func (s StaticKeySet) Keys(ctx context.Context, keyID string) ([]jose.JSONWebKey, error) {
+	return matchKeys(s, keyID), nil
+}
+
+// minKeyRefresh bounds how often RemoteKeySet refetches its keys when it sees
+// an unknown key ID, so that forged tokens cannot flood the JWKS endpoint.
+const minKeyRefresh = time.Minute
+
+// RemoteKeySet is a JSON Web Key Set fetched from a URL. It is refetched
+// when a token names a key that is not in the cached set.
+type RemoteKeySet struct {
+	// URL is the location of the JWKS document.
+	URL string
+
+	// Client is used to fetch the keys. Defaults to http.DefaultClient.
+	Client *http.Client
+
+	mu      sync.Mutex
+	keys    []jose.JSONWebKey
+	fetched time.Time
+}
+
+// NewRemoteKeySet returns a key set fetched from url.
+func NewRemoteKeySet(url string) *RemoteKeySet {
+	return &RemoteKeySet{URL: url}
+}
+
+// Keys implements KeySet.
+func (s *RemoteKeySet) Keys(ctx context.Context, keyID string) ([]jose.JSONWebKey, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if keys := matchKeys(s.keys, keyID); len(keys) > 0 {
+		return keys, nil
+	}
+	if !s.fetched.IsZero() && time.Since(s.fetched) < minKeyRefresh {
+		return nil, nil
+	}
+
+	keys, err := s.fetch(ctx)
+	if err != nil {
+		return nil, err
+	}
+	s.keys, s.fetched = keys, time.Now()
+
+	return matchKeys(s.keys, keyID), nil
+}
+
+func (s *RemoteKeySet) fetch(ctx context.Context) ([]jose.JSONWebKey, error) {
+	client := s.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+
+	req, err := http.NewRequest(http.MethodGet, s.URL, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := client.Do(req.WithContext(ctx))
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("fetching keys from %s: %s", s.URL, resp.Status)
+	}
+
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	var set jose.JSONWebKeySet
+	if err := json.Unmarshal(body, &set); err != nil {
+		return nil, fmt.Errorf("fetching keys from %s: %w", s.URL, err)
+	}
+	return set.Keys, nil
+}
+
+func matchKeys(keys []jose.JSONWebKey, keyID string) []jose.JSONWebKey {
+	if keyID == "" {
+		return keys
+	}
+
+	var matched []jose.JSONWebKey
+	for _, key := range keys {
+		if key.KeyID == keyID {
+			matched = append(matched, key)
+		}
+	}
+	return matched
+}
+
+// VerifySignedToken verifies the signature of an ID token with keys, and
+// then its claims as VerifyToken does. Use it for ID tokens that do not come
+// straight from the token endpoint, such as bearer tokens presented by
+// clients.
+func VerifySignedToken(ctx context.Context, rawToken string, keys KeySet) (*IDToken, error) {
+	if keys == nil {
+		return nil, errors.New("no keys to verify the ID token with")
+	}
+
+	token, err := jwt.ParseSigned(rawToken)
+	if err != nil {
+		return nil, err
+	}
+	if len(token.Headers) != 1 {
+		return nil, errors.New("ID token must have exactly one signature")
+	}
+	header := token.Headers[0]
+
+	candidates, err := keys.Keys(ctx, header.KeyID)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, key := range candidates {
+		if key.Algorithm != "" && key.Algorithm != header.Algorithm {
+			continue
+		}
+
+		var claims idToken
+		if err := token.Claims(key.Key, &claims); err == nil {
+			return newIDToken(&claims)
+		}
+	}
+
+	return nil, errors.New("ID token signature is invalid")
+}
@@ -0,0 +1,101 @@
+/*
+Copyright 2021 The Go jAccount Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package jaccount
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"gopkg.in/square/go-jose.v2"
+	"gopkg.in/square/go-jose.v2/jwt"
+)
+
+func signTestToken(t *testing.T, key *rsa.PrivateKey, keyID string) string {
+	opts := (&jose.SignerOptions{}).WithHeader(jose.HeaderKey("kid"), keyID)
+	signer, err := jose.NewSigner(jose.SigningKey{Algorithm: jose.RS256, Key: key}, opts)
+	if err != nil {
+		t.Fatalf("jose.NewSigner() error = %v", err)
+	}
+
+	raw, err := jwt.Signed(signer).Claims(map[string]interface{}{
+		"iss": Issuer,
+		"aud": "client",
+		"sub": "user",
+		"exp": time.Now().Add(time.Hour).Unix(),
+	}).CompactSerialize()
+	if err != nil {
+		t.Fatalf("CompactSerialize() error = %v", err)
+	}
+	return raw
+}
+
+func TestVerifySignedToken(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("rsa.GenerateKey() error = %v", err)
+	}
+	forged, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("rsa.GenerateKey() error = %v", err)
+	}
+
+	fetches := 0
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fetches++
+		json.NewEncoder(w).Encode(jose.JSONWebKeySet{Keys: []jose.JSONWebKey{
+			{Key: key.Public(), KeyID: "1", Algorithm: "RS256", Use: "sig"},
+		}})
+	}))
+	defer ts.Close()
+
+	keys := NewRemoteKeySet(ts.URL)
+
+	tests := []struct {
+		name    string
+		token   string
+		wantErr bool
+	}{
+		{"valid", signTestToken(t, key, "1"), false},
+		{"forged", signTestToken(t, forged, "1"), true},
+		{"unknown key", signTestToken(t, forged, "2"), true},
+		{"malformed", "not a token", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := VerifySignedToken(context.Background(), tt.token, keys)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("VerifySignedToken() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !tt.wantErr && got.Subject != "user" {
+				t.Errorf("VerifySignedToken() = %+v", got)
+			}
+		})
+	}
+
+	// The unknown key does not refetch the keys within a minute.
+	if fetches != 1 {
+		t.Errorf("keys fetched %d times, want 1", fetches)
+	}
+}
@@ -0,0 +1,326 @@
+/*
+Copyright 2021 The Go jAccount Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package jaccount
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// LessonsService handles communications with the course schedule related
+// methods of the jAccount API.
+type LessonsService service
+
+// Lesson represents a course the user takes in a term, with the time slots it
+// is taught in.
+type Lesson struct {
+	CourseCode string      `json:"courseCode,omitempty"`
+	CourseName string      `json:"courseName,omitempty"`
+	ClassCode  string      `json:"classCode,omitempty"`
+	Term       Term        `json:"term"`
+	Credits    float64     `json:"credits,omitempty"`
+	Teachers   []*Teacher  `json:"teachers,omitempty"`
+	Slots      []*TimeSlot `json:"schedules,omitempty"`
+}
+
+// Teacher represents a teacher of a course.
+type Teacher struct {
+	Account string `json:"account,omitempty"`
+	Name    string `json:"name,omitempty"`
+	Title   string `json:"title,omitempty"`
+}
+
+// TimeSlot represents a weekly time slot of a lesson, spanning the sections
+// from StartSection to EndSection inclusive on Weekday of the given weeks.
+type TimeSlot struct {
+	Weekday      time.Weekday
+	StartSection int
+	EndSection   int
+	Weeks        Weeks
+	Classroom    string
+	Campus       string
+}
+
+type timeSlotJSON struct {
+	Weekday      int    `json:"weekday"`
+	StartSection int    `json:"startSection"`
+	EndSection   int    `json:"endSection"`
+	Weeks        Weeks  `json:"weeks"`
+	Parity       Parity `json:"parity,omitempty"`
+	Classroom    string `json:"classroom,omitempty"`
+	Campus       string `json:"campus,omitempty"`
+}
+
+// InWeek reports whether the slot takes place in the teaching week.
+func (s *TimeSlot) InWeek(week int) bool {
+	return s.Weeks.Contains(week)
+}
+
+// MarshalJSON implements json.Marshaler. The weekday is encoded as 1 for
+// Monday through 7 for Sunday.
+func (s *TimeSlot) MarshalJSON() ([]byte, error) {
+	weekday := int(s.Weekday)
+	if weekday == 0 {
+		weekday = 7
+	}
+	return json.Marshal(&timeSlotJSON{
+		Weekday:      weekday,
+		StartSection: s.StartSection,
+		EndSection:   s.EndSection,
+		Weeks:        s.Weeks,
+		Classroom:    s.Classroom,
+		Campus:       s.Campus,
+	})
+}
+
+// UnmarshalJSON implements json.Unmarshaler. A parity given for the whole
+// slot applies to the week ranges without one.
+func (s *TimeSlot) UnmarshalJSON(data []byte) error {
+	var v timeSlotJSON
+	if err := json.Unmarshal(data, &v); err != nil {
+		return err
+	}
+	if v.Weekday < 1 || v.Weekday > 7 {
+		return fmt.Errorf("invalid weekday %d", v.Weekday)
+	}
+	if v.StartSection < 1 || v.EndSection < v.StartSection {
+		return fmt.Errorf("invalid sections %d-%d", v.StartSection, v.EndSection)
+	}
+
+	for i := range v.Weeks {
+		if v.Weeks[i].Parity == AllWeeks {
+			v.Weeks[i].Parity = v.Parity
+		}
+	}
+
+	*s = TimeSlot{
+		Weekday:      time.Weekday(v.Weekday % 7),
+		StartSection: v.StartSection,
+		EndSection:   v.EndSection,
+		Weeks:        v.Weeks,
+		Classroom:    v.Classroom,
+		Campus:       v.Campus,
+	}
+	return nil
+}
+
+// Parity restricts a week range to its odd or even weeks.
+type Parity int
+
+const (
+	// AllWeeks is every week of a range.
+	AllWeeks Parity = iota
+	// OddWeeks is the odd weeks of a range.
+	OddWeeks
+	// EvenWeeks is the even weeks of a range.
+	EvenWeeks
+)
+
+// MarshalText implements encoding.TextMarshaler.
+func (p Parity) MarshalText() ([]byte, error) {
+	switch p {
+	case OddWeeks:
+		return []byte("odd"), nil
+	case EvenWeeks:
+		return []byte("even"), nil
+	}
+	return []byte(""), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler. Both odd and even and
+// their Chinese forms 单 and 双 are accepted.
+func (p *Parity) UnmarshalText(data []byte) error {
+	switch string(data) {
+	case "", "all":
+		*p = AllWeeks
+	case "odd", "单":
+		*p = OddWeeks
+	case "even", "双":
+		*p = EvenWeeks
+	default:
+		return fmt.Errorf("invalid parity %q", data)
+	}
+	return nil
+}
+
+// WeekRange is a range of teaching weeks from Start to End inclusive.
+type WeekRange struct {
+	Start  int
+	End    int
+	Parity Parity
+}
+
+// Contains reports whether the week is in the range.
+func (r WeekRange) Contains(week int) bool {
+	if week < r.Start || week > r.End {
+		return false
+	}
+	switch r.Parity {
+	case OddWeeks:
+		return week%2 == 1
+	case EvenWeeks:
+		return week%2 == 0
+	}
+	return true
+}
+
+// String returns the range written as 1-16, 1-15(odd) or 3.
+func (r WeekRange) String() string {
+	s := strconv.Itoa(r.Start)
+	if r.End != r.Start {
+		s += "-" + strconv.Itoa(r.End)
+	}
+	if r.Parity != AllWeeks {
+		p, _ := r.Parity.MarshalText()
+		s += "(" + string(p) + ")"
+	}
+	return s
+}
+
+// Weeks is a set of teaching weeks, written as comma-separated ranges such as
+// 1-8,10-16 or 1-15(odd).
+type Weeks []WeekRange
+
+// ParseWeeks parses a set of teaching weeks. Besides the form produced by
+// String, the suffixes 周, 单 and 双 used by the registrar are accepted, as in
+// 1-15周(单).
+func ParseWeeks(s string) (Weeks, error) {
+	var weeks Weeks
+	for _, field := range strings.FieldsFunc(s, func(r rune) bool { return r == ',' || r == '，' }) {
+		field = strings.TrimSpace(field)
+
+		var r WeekRange
+		if i := strings.IndexAny(field, "(（"); i >= 0 {
+			suffix := strings.TrimRight(field[i:], ")）")
+			suffix = strings.TrimLeft(suffix, "(（")
+			if err := r.Parity.UnmarshalText([]byte(suffix)); err != nil {
+				return nil, fmt.Errorf("malformed weeks %q: %v", s, err)
+			}
+			field = field[:i]
+		}
+		field = strings.TrimSuffix(field, "周")
+		switch {
+		case strings.HasSuffix(field, "单"):
+			r.Parity, field = OddWeeks, strings.TrimSuffix(field, "单")
+		case strings.HasSuffix(field, "双"):
+			r.Parity, field = EvenWeeks, strings.TrimSuffix(field, "双")
+		}
+		field = strings.TrimSuffix(field, "周")
+
+		bounds := strings.SplitN(field, "-", 2)
+		start, err := strconv.Atoi(bounds[0])
+		if err != nil {
+			return nil, fmt.Errorf("malformed weeks %q", s)
+		}
+		end := start
+		if len(bounds) == 2 {
+			if end, err = strconv.Atoi(bounds[1]); err != nil {
+				return nil, fmt.Errorf("malformed weeks %q", s)
+			}
+		}
+		if start < 1 || end < start {
+			return nil, fmt.Errorf("malformed weeks %q", s)
+		}
+
+		r.Start, r.End = start, end
+		weeks = append(weeks, r)
+	}
+	return weeks, nil
+}
+
+// Contains reports whether the week is in the set.
+func (w Weeks) Contains(week int) bool {
+	for _, r := range w {
+		if r.Contains(week) {
+			return true
+		}
+	}
+	return false
+}
+
+// List returns the weeks in the set in ascending order.
+func (w Weeks) List() []int {
+	seen := make(map[int]bool)
+	var weeks []int
+	for _, r := range w {
+		for week := r.Start; week <= r.End; week++ {
+			if r.Contains(week) && !seen[week] {
+				seen[week] = true
+				weeks = append(weeks, week)
+			}
+		}
+	}
+	sort.Ints(weeks)
+	return weeks
+}
+
+// String returns the set written as comma-separated ranges.
+func (w Weeks) String() string {
+	ranges := make([]string, len(w))
+	for i, r := range w {
+		ranges[i] = r.String()
+	}
+	return strings.Join(ranges, ",")
+}
+
+// MarshalText implements encoding.TextMarshaler.
+func (w Weeks) MarshalText() ([]byte, error) {
+	return []byte(w.String()), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler.
+func (w *Weeks) UnmarshalText(data []byte) error {
+	weeks, err := ParseWeeks(string(data))
+	if err != nil {
+		return err
+	}
+	*w = weeks
+	return nil
+}
+
+// List returns the lessons of the user in the term.
+//
+// It requires the lessons scope.
+func (s *LessonsService) List(ctx context.Context, term Term) ([]*Lesson, error) {
+	if err := s.client.checkScopes(ScopeLessons); err != nil {
+		return nil, err
+	}
+	if term.IsZero() {
+		return nil, ErrNoTerm
+	}
+
+	path := fmt.Sprintf("/v1/me/lessons/%s", url.PathEscape(term.String()))
+	req, err := s.client.NewRequest(http.MethodGet, path, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var lessons []*Lesson
+	_, err = s.client.Do(ctx, req, &lessons)
+	if err != nil {
+		return nil, err
+	}
+
+	return lessons, nil
+}
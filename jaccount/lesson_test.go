@@ -0,0 +1,137 @@
+/*
+Copyright 2021 The Go jAccount Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package jaccount
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestParseWeeks(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    []int
+		wantErr bool
+	}{
+		{"1-4", []int{1, 2, 3, 4}, false},
+		{"1-8,10,12-13", []int{1, 2, 3, 4, 5, 6, 7, 8, 10, 12, 13}, false},
+		{"1-7(odd)", []int{1, 3, 5, 7}, false},
+		{"1-8周(双)", []int{2, 4, 6, 8}, false},
+		{"2-6双", []int{2, 4, 6}, false},
+		{"1-3，5周", []int{1, 2, 3, 5}, false},
+		{"8-1", nil, true},
+		{"1-x", nil, true},
+		{"1-8(每)", nil, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.in, func(t *testing.T) {
+			weeks, err := ParseWeeks(tt.in)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseWeeks() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got := weeks.List(); !tt.wantErr && !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ParseWeeks().List() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseTerm(t *testing.T) {
+	term, err := ParseTerm("2026-2027-1")
+	if err != nil || term != (Term{Year: 2026, Semester: Fall}) {
+		t.Errorf("ParseTerm() = %v, %v", term, err)
+	}
+	if got := term.String(); got != "2026-2027-1" {
+		t.Errorf("Term.String() = %q", got)
+	}
+
+	for _, s := range []string{"2026-2028-1", "2026-2027-4", "2026"} {
+		if _, err := ParseTerm(s); err == nil {
+			t.Errorf("ParseTerm(%q) error = nil, want error", s)
+		}
+	}
+}
+
+func TestLessonsService_ListNoTerm(t *testing.T) {
+	client := NewClient(nil)
+	if _, err := client.Lessons.List(context.Background(), Term{}); !errors.Is(err, ErrNoTerm) {
+		t.Errorf("LessonsService.List() error = %v, want %v", err, ErrNoTerm)
+	}
+}
+
+func TestTerm_MarshalText(t *testing.T) {
+	for _, term := range []Term{{Year: 2026, Semester: Spring}, {}} {
+		data, err := term.MarshalText()
+		if err != nil {
+			t.Fatalf("Term.MarshalText() error = %v", err)
+		}
+		if term.IsZero() && len(data) != 0 {
+			t.Errorf("Term.MarshalText() = %q, want empty", data)
+		}
+
+		got := Term{Year: 1}
+		if err := got.UnmarshalText(data); err != nil || got != term {
+			t.Errorf("Term.UnmarshalText(%q) = %v, %v, want %v", data, got, err, term)
+		}
+	}
+}
+
+func TestLessonsService_List(t *testing.T) {
+	client, mux, teardown := setup(t)
+	defer teardown()
+
+	mux.HandleFunc("/v1/me/lessons/2026-2027-1", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, http.MethodGet)
+		fmt.Fprint(w, `{"errno":0,"error":"success","entities":[{
+			"courseCode":"CS1501","courseName":"程序设计","term":"2026-2027-1","credits":4,
+			"teachers":[{"name":"张老师","title":"教授"}],
+			"schedules":[
+				{"weekday":1,"startSection":3,"endSection":4,"weeks":"1-16","classroom":"东上院102"},
+				{"weekday":7,"startSection":1,"endSection":2,"weeks":"1-15","parity":"单","classroom":"东上院102"}
+			]}]}`)
+	})
+
+	lessons, err := client.Lessons.List(context.Background(), Term{Year: 2026, Semester: Fall})
+	if err != nil {
+		t.Fatalf("LessonsService.List() error = %v", err)
+	}
+	if len(lessons) != 1 || len(lessons[0].Slots) != 2 {
+		t.Fatalf("LessonsService.List() = %+v", lessons)
+	}
+
+	lesson := lessons[0]
+	if lesson.Term.Semester != Fall || lesson.Teachers[0].Name != "张老师" {
+		t.Errorf("LessonsService.List() lesson = %+v", lesson)
+	}
+
+	sunday := lesson.Slots[1]
+	if sunday.Weekday != time.Sunday || sunday.StartSection != 1 || sunday.EndSection != 2 {
+		t.Errorf("LessonsService.List() slot = %+v", sunday)
+	}
+	if !sunday.InWeek(3) || sunday.InWeek(4) || sunday.InWeek(17) {
+		t.Errorf("TimeSlot.InWeek() mismatch for %v", sunday.Weeks)
+	}
+	if got := sunday.Weeks.String(); got != "1-15(odd)" {
+		t.Errorf("Weeks.String() = %q", got)
+	}
+}
@@ -0,0 +1,211 @@
+/*
+Copyright 2021 The Go jAccount Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package jaccount
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+
+	"github.com/google/go-querystring/query"
+)
+
+// MailService handles communications with the campus mailbox related methods
+// of the jAccount API.
+type MailService service
+
+// MailFolder represents a folder in the mailbox of the user.
+type MailFolder struct {
+	ID     string `json:"id,omitempty"`
+	Name   string `json:"name,omitempty"`
+	Total  int    `json:"total,omitempty"`
+	Unread int    `json:"unread,omitempty"`
+}
+
+// MailAddress represents a mailbox address with an optional display name.
+type MailAddress struct {
+	Name    string `json:"name,omitempty"`
+	Address string `json:"address,omitempty"`
+}
+
+// String returns the address in the form of "Name <address>".
+func (a *MailAddress) String() string {
+	if a.Name == "" {
+		return a.Address
+	}
+	return fmt.Sprintf("%s <%s>", a.Name, a.Address)
+}
+
+// MailMessage represents a message in the mailbox of the user. Headers and
+// Parts are only populated by MailService.Get.
+type MailMessage struct {
+	ID          string              `json:"id,omitempty"`
+	FolderID    string              `json:"folderId,omitempty"`
+	MessageID   string              `json:"messageId,omitempty"`
+	Subject     string              `json:"subject,omitempty"`
+	From        *MailAddress        `json:"from,omitempty"`
+	To          []*MailAddress      `json:"to,omitempty"`
+	Cc          []*MailAddress      `json:"cc,omitempty"`
+	Bcc         []*MailAddress      `json:"bcc,omitempty"`
+	Date        Timestamp           `json:"date"`
+	Size        int64               `json:"size,omitempty"`
+	Seen        bool                `json:"seen,omitempty"`
+	Flagged     bool                `json:"flagged,omitempty"`
+	Headers     map[string][]string `json:"headers,omitempty"`
+	Parts       []*MailPart         `json:"parts,omitempty"`
+	Attachments []*MailAttachment   `json:"attachments,omitempty"`
+}
+
+// MailPart represents a body part of a message, such as its plain-text or HTML
+// alternative.
+type MailPart struct {
+	ContentType string `json:"contentType,omitempty"`
+	Charset     string `json:"charset,omitempty"`
+	Content     string `json:"content,omitempty"`
+}
+
+// MailAttachment represents the metadata of an attachment of a message.
+type MailAttachment struct {
+	ID          string `json:"id,omitempty"`
+	Filename    string `json:"filename,omitempty"`
+	ContentType string `json:"contentType,omitempty"`
+	ContentID   string `json:"contentId,omitempty"`
+	Inline      bool   `json:"inline,omitempty"`
+	Size        int64  `json:"size,omitempty"`
+}
+
+// MailListOptions specifies the optional parameters to the MailService.List
+// method.
+type MailListOptions struct {
+	// Seen filters messages by seen state if set.
+	Seen *bool `url:"seen,omitempty"`
+
+	ListOptions
+}
+
+// ListFolders returns the folders in the mailbox of the user.
+//
+// It requires the read_mails scope.
+func (s *MailService) ListFolders(ctx context.Context) ([]*MailFolder, error) {
+	if err := s.client.checkScopes(ScopeReadMails); err != nil {
+		return nil, err
+	}
+
+	req, err := s.client.NewRequest(http.MethodGet, "/v1/me/mail/folders", nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var folders []*MailFolder
+	_, err = s.client.Do(ctx, req, &folders)
+	if err != nil {
+		return nil, err
+	}
+
+	return folders, nil
+}
+
+// List returns a page of the messages in the folder, newest first.
+//
+// It requires the read_mails scope.
+func (s *MailService) List(ctx context.Context, folderID string, opts *MailListOptions) ([]*MailMessage, *Response, error) {
+	if err := s.client.checkScopes(ScopeReadMails); err != nil {
+		return nil, nil, err
+	}
+
+	values, err := query.Values(opts)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	path := fmt.Sprintf("/v1/me/mail/folders/%s/messages", url.PathEscape(folderID))
+	req, err := s.client.NewRequest(http.MethodGet, path, values)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var messages []*MailMessage
+	response, _, err := s.client.do(ctx, req, &messages)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return messages, response, nil
+}
+
+// Get returns the message with the given ID, including its headers, body
+// parts and attachment metadata.
+//
+// It requires the read_mails scope.
+func (s *MailService) Get(ctx context.Context, id string) (*MailMessage, error) {
+	if err := s.client.checkScopes(ScopeReadMails); err != nil {
+		return nil, err
+	}
+
+	req, err := s.client.NewRequest(http.MethodGet, mailPath(id), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	message := make([]MailMessage, 1)
+	_, err = s.client.Do(ctx, req, &message)
+	if err != nil {
+		return nil, err
+	}
+	if len(message) == 0 {
+		return nil, ErrNoEntities
+	}
+
+	return &message[0], nil
+}
+
+// GetRaw returns the message with the given ID in its original RFC 5322
+// form. The caller must close the returned reader.
+//
+// It requires the read_mails scope.
+func (s *MailService) GetRaw(ctx context.Context, id string) (io.ReadCloser, error) {
+	return s.download(ctx, mailPath(id)+"/raw")
+}
+
+// DownloadAttachment returns the content of an attachment of the message.
+// The caller must close the returned reader.
+//
+// It requires the read_mails scope.
+func (s *MailService) DownloadAttachment(ctx context.Context, messageID string, attachmentID string) (io.ReadCloser, error) {
+	path := fmt.Sprintf("%s/attachments/%s", mailPath(messageID), url.PathEscape(attachmentID))
+	return s.download(ctx, path)
+}
+
+func (s *MailService) download(ctx context.Context, path string) (io.ReadCloser, error) {
+	if err := s.client.checkScopes(ScopeReadMails); err != nil {
+		return nil, err
+	}
+
+	req, err := s.client.NewRequest(http.MethodGet, path, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	body, _, err := s.client.doStream(ctx, req)
+	return body, err
+}
+
+func mailPath(id string) string {
+	return fmt.Sprintf("/v1/me/mail/messages/%s", url.PathEscape(id))
+}
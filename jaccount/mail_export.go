@@ -0,0 +1,205 @@
+/*
+Copyright 2021 The Go jAccount Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package jaccount
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// ExportMbox writes every message in the folder to w in the mboxrd format and
+// returns the number of messages written.
+//
+// It requires the read_mails scope.
+func (s *MailService) ExportMbox(ctx context.Context, folderID string, w io.Writer) (int, error) {
+	bw := bufio.NewWriter(w)
+
+	n := 0
+	err := s.walk(ctx, folderID, func(m *MailMessage) error {
+		raw, err := s.GetRaw(ctx, m.ID)
+		if err != nil {
+			return err
+		}
+		defer raw.Close()
+
+		sender := "MAILER-DAEMON"
+		if m.From != nil && m.From.Address != "" {
+			sender = m.From.Address
+		}
+		fmt.Fprintf(bw, "From %s %s\n", sender, m.Date.UTC().Format(time.ANSIC))
+
+		if err := writeMboxrd(bw, raw); err != nil {
+			return err
+		}
+		n++
+		return nil
+	})
+	if err != nil {
+		// Keep the messages written so far.
+		bw.Flush()
+		return n, err
+	}
+
+	return n, bw.Flush()
+}
+
+// writeMboxrd copies a message into an mbox, converting line endings to LF
+// and quoting lines that begin with any number of '>' followed by "From ".
+func writeMboxrd(w *bufio.Writer, r io.Reader) error {
+	br := bufio.NewReader(r)
+	for {
+		line, err := br.ReadString('\n')
+		if len(line) > 0 {
+			line = strings.TrimSuffix(strings.TrimSuffix(line, "\n"), "\r")
+			if strings.HasPrefix(strings.TrimLeft(line, ">"), "From ") {
+				w.WriteByte('>')
+			}
+			w.WriteString(line)
+			w.WriteByte('\n')
+		}
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return err
+		}
+	}
+
+	// Messages are separated by an empty line.
+	_, err := w.WriteString("\n")
+	return err
+}
+
+// ExportMaildir writes every message in the folder into the Maildir at dir,
+// creating it if needed, and returns the number of messages written. Messages
+// exported before are skipped, so that exporting the folder again only adds
+// the new messages.
+//
+// It requires the read_mails scope.
+func (s *MailService) ExportMaildir(ctx context.Context, folderID string, dir string) (int, error) {
+	for _, sub := range []string{"tmp", "new", "cur"} {
+		if err := os.MkdirAll(filepath.Join(dir, sub), 0700); err != nil {
+			return 0, err
+		}
+	}
+
+	existing := make(map[string]bool)
+	for _, sub := range []string{"new", "cur"} {
+		files, err := ioutil.ReadDir(filepath.Join(dir, sub))
+		if err != nil {
+			return 0, err
+		}
+		for _, f := range files {
+			existing[strings.SplitN(f.Name(), ":", 2)[0]] = true
+		}
+	}
+
+	n := 0
+	err := s.walk(ctx, folderID, func(m *MailMessage) error {
+		name := maildirName(m)
+		if existing[name] {
+			return nil
+		}
+
+		raw, err := s.GetRaw(ctx, m.ID)
+		if err != nil {
+			return err
+		}
+		defer raw.Close()
+
+		// Messages are written to tmp and then moved into place, so that
+		// readers of the Maildir never see a partial message.
+		tmp := filepath.Join(dir, "tmp", name)
+		f, err := os.OpenFile(tmp, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0600)
+		if err != nil {
+			return err
+		}
+		_, err = io.Copy(f, raw)
+		if cerr := f.Close(); err == nil {
+			err = cerr
+		}
+		if err != nil {
+			os.Remove(tmp)
+			return err
+		}
+
+		dst := filepath.Join(dir, "new", name)
+		if m.Seen || m.Flagged {
+			dst = filepath.Join(dir, "cur", name+":2,"+maildirFlags(m))
+		}
+		if err := os.Rename(tmp, dst); err != nil {
+			return err
+		}
+
+		existing[name] = true
+		n++
+		return nil
+	})
+
+	return n, err
+}
+
+// maildirName returns a unique name for the message, stable across exports.
+// The ID is hashed, as it may contain characters not allowed in file names.
+func maildirName(m *MailMessage) string {
+	sum := sha256.Sum256([]byte(m.ID))
+	return fmt.Sprintf("%d.%s.jaccount", m.Date.Unix(), hex.EncodeToString(sum[:16]))
+}
+
+// maildirFlags returns the Maildir info flags of the message in ASCII order.
+func maildirFlags(m *MailMessage) string {
+	var flags bytes.Buffer
+	if m.Flagged {
+		flags.WriteByte('F')
+	}
+	if m.Seen {
+		flags.WriteByte('S')
+	}
+	return flags.String()
+}
+
+// walk calls fn for every message in the folder, following pagination.
+func (s *MailService) walk(ctx context.Context, folderID string, fn func(*MailMessage) error) error {
+	opts := &MailListOptions{}
+	for {
+		messages, response, err := s.List(ctx, folderID, opts)
+		if err != nil {
+			return err
+		}
+
+		for _, m := range messages {
+			if err := fn(m); err != nil {
+				return err
+			}
+		}
+
+		if response.NextToken == "" || len(messages) == 0 {
+			return nil
+		}
+		opts.NextToken = response.NextToken
+	}
+}
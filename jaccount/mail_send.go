@@ -0,0 +1,331 @@
+/*
+Copyright 2021 The Go jAccount Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package jaccount
+
+import (
+	"bufio"
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"mime"
+	"mime/multipart"
+	"mime/quotedprintable"
+	"net/http"
+	"net/mail"
+	"net/textproto"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// OutgoingMail represents a message to be sent by MailService.Send.
+type OutgoingMail struct {
+	To  []*MailAddress
+	Cc  []*MailAddress
+	Bcc []*MailAddress
+
+	Subject string
+
+	// Text and HTML are the plain-text and HTML alternatives of the body. At
+	// least one of them should be set.
+	Text string
+	HTML string
+
+	Attachments []*Attachment
+}
+
+// Attachment represents a file attached to an OutgoingMail.
+type Attachment struct {
+	// Filename is the name of the file shown to the recipients. It defaults
+	// to the base name of Path.
+	Filename string
+
+	// ContentType defaults to the type registered for the extension of
+	// Filename, or application/octet-stream.
+	ContentType string
+
+	// Content is read while the message is sent. If it is nil, the file at
+	// Path is opened instead. Content is closed after use if it is an
+	// io.Closer.
+	Content io.Reader
+	Path    string
+}
+
+// Send sends the message from the campus address of the user and returns the
+// Message-ID assigned by the server. The message is encoded while it is sent,
+// so attachments are streamed rather than buffered.
+//
+// The Bcc header is included in the submitted message, and removed by the
+// server before delivery.
+//
+// It requires the send_mail scope.
+func (s *MailService) Send(ctx context.Context, m *OutgoingMail) (string, error) {
+	if err := s.client.checkScopes(ScopeSendMail); err != nil {
+		return "", err
+	}
+
+	if len(m.To)+len(m.Cc)+len(m.Bcc) == 0 {
+		return "", errors.New("no recipients")
+	}
+
+	pr, pw := io.Pipe()
+	go func() {
+		pw.CloseWithError(m.writeTo(pw))
+	}()
+	defer pr.Close()
+
+	req, err := s.client.NewUploadRequest(http.MethodPost, "/v1/me/mail/messages", pr, -1, "message/rfc822")
+	if err != nil {
+		return "", err
+	}
+
+	var result []struct {
+		MessageID string `json:"messageId"`
+	}
+	_, err = s.client.Do(ctx, req, &result)
+	if err != nil {
+		return "", err
+	}
+	if len(result) == 0 {
+		return "", ErrNoEntities
+	}
+
+	return result[0].MessageID, nil
+}
+
+// writeTo writes the message in MIME format.
+func (m *OutgoingMail) writeTo(w io.Writer) error {
+	bw := bufio.NewWriter(w)
+
+	header := make(textproto.MIMEHeader)
+	header.Set("MIME-Version", "1.0")
+	header.Set("Date", time.Now().Format(time.RFC1123Z))
+	header.Set("Subject", mime.BEncoding.Encode("utf-8", m.Subject))
+	for _, field := range []struct {
+		name  string
+		addrs []*MailAddress
+	}{{"To", m.To}, {"Cc", m.Cc}, {"Bcc", m.Bcc}} {
+		if len(field.addrs) > 0 {
+			header.Set(field.name, formatAddressList(field.addrs))
+		}
+	}
+
+	if len(m.Attachments) == 0 {
+		err := m.writeBody(header, func(header textproto.MIMEHeader) (io.Writer, error) {
+			return bw, writeHeader(bw, header)
+		})
+		if err != nil {
+			return err
+		}
+		return bw.Flush()
+	}
+
+	mw := multipart.NewWriter(bw)
+	header.Set("Content-Type", "multipart/mixed; boundary="+mw.Boundary())
+	if err := writeHeader(bw, header); err != nil {
+		return err
+	}
+	if err := m.writeBody(make(textproto.MIMEHeader), mw.CreatePart); err != nil {
+		return err
+	}
+	for _, a := range m.Attachments {
+		if err := a.writeTo(mw); err != nil {
+			return err
+		}
+	}
+	if err := mw.Close(); err != nil {
+		return err
+	}
+
+	return bw.Flush()
+}
+
+// writeBody completes header with the content type of the body alternatives,
+// writes it with create and then writes the body to the returned writer.
+func (m *OutgoingMail) writeBody(header textproto.MIMEHeader, create func(textproto.MIMEHeader) (io.Writer, error)) error {
+	if m.Text != "" && m.HTML != "" {
+		boundary := multipart.NewWriter(ioutil.Discard).Boundary()
+		header.Set("Content-Type", "multipart/alternative; boundary="+boundary)
+		w, err := create(header)
+		if err != nil {
+			return err
+		}
+
+		mw := multipart.NewWriter(w)
+		if err := mw.SetBoundary(boundary); err != nil {
+			return err
+		}
+		if err := writeTextPart(mw, "text/plain", m.Text); err != nil {
+			return err
+		}
+		if err := writeTextPart(mw, "text/html", m.HTML); err != nil {
+			return err
+		}
+		return mw.Close()
+	}
+
+	contentType, content := "text/plain", m.Text
+	if m.HTML != "" {
+		contentType, content = "text/html", m.HTML
+	}
+	header.Set("Content-Type", contentType+"; charset=utf-8")
+	header.Set("Content-Transfer-Encoding", "quoted-printable")
+	w, err := create(header)
+	if err != nil {
+		return err
+	}
+	return writeQuotedPrintable(w, content)
+}
+
+func writeTextPart(mw *multipart.Writer, contentType string, content string) error {
+	header := make(textproto.MIMEHeader)
+	header.Set("Content-Type", contentType+"; charset=utf-8")
+	header.Set("Content-Transfer-Encoding", "quoted-printable")
+	w, err := mw.CreatePart(header)
+	if err != nil {
+		return err
+	}
+	return writeQuotedPrintable(w, content)
+}
+
+func writeQuotedPrintable(w io.Writer, content string) error {
+	qw := quotedprintable.NewWriter(w)
+	if _, err := io.WriteString(qw, content); err != nil {
+		return err
+	}
+	return qw.Close()
+}
+
+func (a *Attachment) writeTo(mw *multipart.Writer) error {
+	content := a.Content
+	if content == nil {
+		f, err := os.Open(a.Path)
+		if err != nil {
+			return err
+		}
+		content = f
+	}
+	if c, ok := content.(io.Closer); ok {
+		defer c.Close()
+	}
+
+	filename := a.Filename
+	if filename == "" {
+		filename = filepath.Base(a.Path)
+	}
+	contentType := a.ContentType
+	if contentType == "" {
+		contentType = mime.TypeByExtension(filepath.Ext(filename))
+	}
+	if contentType == "" {
+		contentType = "application/octet-stream"
+	}
+
+	mediaType, params, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return fmt.Errorf("attachment %s: %v", filename, err)
+	}
+	params["name"] = filename
+
+	// Non-ASCII file names are encoded as specified by RFC 2231.
+	header := make(textproto.MIMEHeader)
+	header.Set("Content-Type", mime.FormatMediaType(mediaType, params))
+	header.Set("Content-Disposition", mime.FormatMediaType("attachment", map[string]string{"filename": filename}))
+	header.Set("Content-Transfer-Encoding", "base64")
+	w, err := mw.CreatePart(header)
+	if err != nil {
+		return err
+	}
+
+	lw := &lineWriter{w: w}
+	enc := base64.NewEncoder(base64.StdEncoding, lw)
+	if _, err := io.Copy(enc, content); err != nil {
+		return err
+	}
+	if err := enc.Close(); err != nil {
+		return err
+	}
+	if lw.col > 0 {
+		_, err = io.WriteString(w, "\r\n")
+	}
+	return err
+}
+
+// writeHeader writes the header of a message followed by the blank line that
+// separates it from the body.
+func writeHeader(w io.Writer, header textproto.MIMEHeader) error {
+	keys := make([]string, 0, len(header))
+	for k := range header {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	for _, k := range keys {
+		for _, v := range header[k] {
+			if _, err := fmt.Fprintf(w, "%s: %s\r\n", k, v); err != nil {
+				return err
+			}
+		}
+	}
+	_, err := io.WriteString(w, "\r\n")
+	return err
+}
+
+// formatAddressList formats addresses for an address header, encoding
+// non-ASCII display names as RFC 2047 encoded-words.
+func formatAddressList(addrs []*MailAddress) string {
+	list := make([]string, len(addrs))
+	for i, a := range addrs {
+		list[i] = (&mail.Address{Name: a.Name, Address: a.Address}).String()
+	}
+	return strings.Join(list, ",\r\n ")
+}
+
+// lineWriter breaks base64 output into lines of 76 characters.
+type lineWriter struct {
+	w   io.Writer
+	col int
+}
+
+func (l *lineWriter) Write(p []byte) (int, error) {
+	n := 0
+	for len(p) > 0 {
+		chunk := 76 - l.col
+		if chunk > len(p) {
+			chunk = len(p)
+		}
+		m, err := l.w.Write(p[:chunk])
+		n += m
+		if err != nil {
+			return n, err
+		}
+		p = p[chunk:]
+		l.col += chunk
+		if l.col == 76 {
+			if _, err := io.WriteString(l.w, "\r\n"); err != nil {
+				return n, err
+			}
+			l.col = 0
+		}
+	}
+	return n, nil
+}
@@ -0,0 +1,137 @@
+/*
+Copyright 2021 The Go jAccount Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package jaccount
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"errors"
+	"io/ioutil"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"net/mail"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestMailService_Send(t *testing.T) {
+	client, mux, teardown := setup(t)
+	defer teardown()
+
+	attachment := bytes.Repeat([]byte("0123456789"), 100)
+	dir, err := ioutil.TempDir("", "mail")
+	if err != nil {
+		t.Fatalf("error = %v", err)
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "成绩单.pdf")
+	if err := ioutil.WriteFile(path, attachment, 0600); err != nil {
+		t.Fatalf("error = %v", err)
+	}
+
+	dec := new(mime.WordDecoder)
+	mux.HandleFunc("/v1/me/mail/messages", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, http.MethodPost)
+		if got := r.Header.Get("Content-Type"); got != "message/rfc822" {
+			t.Errorf("Content-Type = %q", got)
+		}
+
+		msg, err := mail.ReadMessage(r.Body)
+		if err != nil {
+			t.Fatalf("mail.ReadMessage() error = %v", err)
+		}
+		if subject, _ := dec.DecodeHeader(msg.Header.Get("Subject")); subject != "期中考试安排" {
+			t.Errorf("Subject = %q", subject)
+		}
+		to, err := msg.Header.AddressList("To")
+		if err != nil || len(to) != 2 || to[0].Name != "张三" || to[1].Address != "bob@sjtu.edu.cn" {
+			t.Errorf("To = %v, %v", to, err)
+		}
+		if bcc := msg.Header.Get("Bcc"); bcc != "<carol@sjtu.edu.cn>" {
+			t.Errorf("Bcc = %q", bcc)
+		}
+
+		_, params, _ := mime.ParseMediaType(msg.Header.Get("Content-Type"))
+		mr := multipart.NewReader(msg.Body, params["boundary"])
+
+		body, err := mr.NextPart()
+		if err != nil {
+			t.Fatalf("NextPart() error = %v", err)
+		}
+		if ct := body.Header.Get("Content-Type"); !strings.HasPrefix(ct, "multipart/alternative") {
+			t.Errorf("body Content-Type = %q", ct)
+		}
+
+		file, err := mr.NextPart()
+		if err != nil {
+			t.Fatalf("NextPart() error = %v", err)
+		}
+		if name := file.FileName(); name != "成绩单.pdf" {
+			t.Errorf("FileName() = %q", name)
+		}
+		if ct := file.Header.Get("Content-Type"); !strings.HasPrefix(ct, "application/pdf") {
+			t.Errorf("attachment Content-Type = %q", ct)
+		}
+		content, err := ioutil.ReadAll(base64.NewDecoder(base64.StdEncoding, file))
+		if err != nil || !bytes.Equal(content, attachment) {
+			t.Errorf("attachment content = %q, %v", content, err)
+		}
+
+		writeResponse(t, w, []map[string]string{{"messageId": "<1@mail.sjtu.edu.cn>"}}, 0, "")
+	})
+
+	id, err := client.Mail.Send(context.Background(), &OutgoingMail{
+		To:          []*MailAddress{{Name: "张三", Address: "zhangsan@sjtu.edu.cn"}, {Address: "bob@sjtu.edu.cn"}},
+		Bcc:         []*MailAddress{{Address: "carol@sjtu.edu.cn"}},
+		Subject:     "期中考试安排",
+		Text:        "请查收附件。",
+		HTML:        "<p>请查收附件。</p>",
+		Attachments: []*Attachment{{Path: path}},
+	})
+	if err != nil {
+		t.Fatalf("MailService.Send() error = %v", err)
+	}
+	if id != "<1@mail.sjtu.edu.cn>" {
+		t.Errorf("MailService.Send() = %q", id)
+	}
+}
+
+func TestMailService_SendNoRecipients(t *testing.T) {
+	client := NewClient(nil)
+	if _, err := client.Mail.Send(context.Background(), &OutgoingMail{Subject: "Hi"}); err == nil {
+		t.Error("MailService.Send() error = nil, want error")
+	}
+}
+
+func TestMailService_SendNoEntities(t *testing.T) {
+	client, mux, teardown := setup(t)
+	defer teardown()
+
+	mux.HandleFunc("/v1/me/mail/messages", func(w http.ResponseWriter, r *http.Request) {
+		ioutil.ReadAll(r.Body)
+		writeResponse(t, w, []struct{}{}, 0, "")
+	})
+
+	m := &OutgoingMail{To: []*MailAddress{{Address: "bob@sjtu.edu.cn"}}, Subject: "Hi", Text: "Hello"}
+	if _, err := client.Mail.Send(context.Background(), m); !errors.Is(err, ErrNoEntities) {
+		t.Errorf("MailService.Send() error = %v, want %v", err, ErrNoEntities)
+	}
+}
@@ -0,0 +1,200 @@
+/*
+Copyright 2021 The Go jAccount Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package jaccount
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// setupMailbox registers a folder "INBOX" holding two messages served over
+// two pages.
+func setupMailbox(t *testing.T, mux *http.ServeMux) {
+	date := Timestamp{time.Date(2026, 10, 1, 8, 0, 0, 0, time.UTC)}
+	messages := []*MailMessage{
+		{ID: "m1", Subject: "Hello", From: &MailAddress{Address: "alice@sjtu.edu.cn"}, Date: date, Seen: true},
+		{ID: "m2", Subject: "Again", Date: date},
+	}
+
+	mux.HandleFunc("/v1/me/mail/folders/INBOX/messages", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, http.MethodGet)
+		if r.URL.Query().Get("nextToken") == "" {
+			writeResponse(t, w, messages[:1], 2, "page2")
+		} else {
+			writeResponse(t, w, messages[1:], 2, "")
+		}
+	})
+	mux.HandleFunc("/v1/me/mail/messages/m1/raw", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "message/rfc822")
+		w.Write([]byte("Subject: Hello\r\n\r\nFrom here\r\n>From there\r\n"))
+	})
+	mux.HandleFunc("/v1/me/mail/messages/m2/raw", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "message/rfc822")
+		w.Write([]byte("Subject: Again\r\n\r\nBody\r\n"))
+	})
+}
+
+func TestMailService_Get(t *testing.T) {
+	client, mux, teardown := setup(t)
+	defer teardown()
+
+	mux.HandleFunc("/v1/me/mail/messages/m1", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, http.MethodGet)
+		writeResponse(t, w, []*MailMessage{{
+			ID:          "m1",
+			Headers:     map[string][]string{"Subject": {"Hello"}},
+			Parts:       []*MailPart{{ContentType: "text/plain", Content: "Hi"}},
+			Attachments: []*MailAttachment{{ID: "a1", Filename: "report.pdf", Size: 5}},
+		}}, 0, "")
+	})
+	mux.HandleFunc("/v1/me/mail/messages/m1/attachments/a1", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/pdf")
+		w.Write([]byte("%PDF-"))
+	})
+	mux.HandleFunc("/v1/me/mail/messages/m1/attachments/a2", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusNotFound)
+		json.NewEncoder(w).Encode(map[string]interface{}{"errno": 404, "error": "attachment not found"})
+	})
+
+	ctx := context.Background()
+	message, err := client.Mail.Get(ctx, "m1")
+	if err != nil {
+		t.Fatalf("MailService.Get() error = %v", err)
+	}
+	if message.Headers["Subject"][0] != "Hello" || len(message.Parts) != 1 || len(message.Attachments) != 1 {
+		t.Errorf("MailService.Get() = %+v", message)
+	}
+
+	body, err := client.Mail.DownloadAttachment(ctx, "m1", "a1")
+	if err != nil {
+		t.Fatalf("MailService.DownloadAttachment() error = %v", err)
+	}
+	content, _ := ioutil.ReadAll(body)
+	body.Close()
+	if string(content) != "%PDF-" {
+		t.Errorf("MailService.DownloadAttachment() content = %q", content)
+	}
+
+	_, err = client.Mail.DownloadAttachment(ctx, "m1", "a2")
+	if e, ok := err.(*ErrorResponse); !ok || e.ErrNO != 404 {
+		t.Errorf("MailService.DownloadAttachment() error = %v, want ErrorResponse", err)
+	}
+}
+
+func TestMailService_ExportMbox(t *testing.T) {
+	client, mux, teardown := setup(t)
+	defer teardown()
+	setupMailbox(t, mux)
+
+	var buf bytes.Buffer
+	n, err := client.Mail.ExportMbox(context.Background(), "INBOX", &buf)
+	if err != nil || n != 2 {
+		t.Fatalf("MailService.ExportMbox() = %d, %v, want 2", n, err)
+	}
+
+	want := "From alice@sjtu.edu.cn Thu Oct  1 08:00:00 2026\n" +
+		"Subject: Hello\n\n>From here\n>>From there\n\n" +
+		"From MAILER-DAEMON Thu Oct  1 08:00:00 2026\n" +
+		"Subject: Again\n\nBody\n\n"
+	if got := buf.String(); got != want {
+		t.Errorf("MailService.ExportMbox() wrote\n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestMailService_ExportMboxError(t *testing.T) {
+	client, mux, teardown := setup(t)
+	defer teardown()
+
+	date := Timestamp{time.Date(2026, 10, 1, 8, 0, 0, 0, time.UTC)}
+	mux.HandleFunc("/v1/me/mail/folders/INBOX/messages", func(w http.ResponseWriter, r *http.Request) {
+		writeResponse(t, w, []*MailMessage{
+			{ID: "m1", From: &MailAddress{Address: "alice@sjtu.edu.cn"}, Date: date},
+			{ID: "m2", Date: date},
+		}, 2, "")
+	})
+	mux.HandleFunc("/v1/me/mail/messages/m1/raw", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("Subject: Hello\r\n\r\nBody\r\n"))
+	})
+	mux.HandleFunc("/v1/me/mail/messages/m2/raw", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	})
+
+	var buf bytes.Buffer
+	n, err := client.Mail.ExportMbox(context.Background(), "INBOX", &buf)
+	if err == nil || n != 1 {
+		t.Fatalf("MailService.ExportMbox() = %d, %v, want 1 and an error", n, err)
+	}
+
+	// The failed message leaves no separator behind.
+	want := "From alice@sjtu.edu.cn Thu Oct  1 08:00:00 2026\n" +
+		"Subject: Hello\n\nBody\n\n"
+	if got := buf.String(); got != want {
+		t.Errorf("MailService.ExportMbox() wrote\n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestMailService_ExportMaildir(t *testing.T) {
+	client, mux, teardown := setup(t)
+	defer teardown()
+	setupMailbox(t, mux)
+
+	dir, err := ioutil.TempDir("", "maildir")
+	if err != nil {
+		t.Fatalf("error = %v", err)
+	}
+	defer os.RemoveAll(dir)
+
+	ctx := context.Background()
+	n, err := client.Mail.ExportMaildir(ctx, "INBOX", dir)
+	if err != nil || n != 2 {
+		t.Fatalf("MailService.ExportMaildir() = %d, %v, want 2", n, err)
+	}
+
+	date := Timestamp{time.Date(2026, 10, 1, 8, 0, 0, 0, time.UTC)}
+	if _, err := os.Stat(filepath.Join(dir, "cur", maildirName(&MailMessage{ID: "m1", Date: date})+":2,S")); err != nil {
+		t.Errorf("seen message not in cur: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "new", maildirName(&MailMessage{ID: "m2", Date: date}))); err != nil {
+		t.Errorf("unseen message not in new: %v", err)
+	}
+
+	n, err = client.Mail.ExportMaildir(ctx, "INBOX", dir)
+	if err != nil || n != 0 {
+		t.Errorf("MailService.ExportMaildir() again = %d, %v, want 0", n, err)
+	}
+}
+
+func TestMaildirName(t *testing.T) {
+	date := Timestamp{time.Date(2026, 10, 1, 8, 0, 0, 0, time.UTC)}
+	a := maildirName(&MailMessage{ID: "a.b", Date: date})
+	b := maildirName(&MailMessage{ID: "a-b", Date: date})
+	if a == b {
+		t.Errorf("maildirName() = %q for distinct IDs", a)
+	}
+	if strings.ContainsAny(a, "/:") {
+		t.Errorf("maildirName() = %q, want a valid file name", a)
+	}
+}
@@ -0,0 +1,169 @@
+/*
+Copyright 2021 The Go jAccount Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package jaccount
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+
+	"github.com/google/go-querystring/query"
+)
+
+// NotificationService handles communications with the notification related
+// methods of the jAccount API.
+type NotificationService service
+
+// Notification represents a notification sent to the user.
+type Notification struct {
+	ID        string     `json:"id,omitempty"`
+	Title     string     `json:"title,omitempty"`
+	Content   string     `json:"content,omitempty"`
+	URL       string     `json:"url,omitempty"`
+	Sender    *Sender    `json:"sender,omitempty"`
+	Priority  Priority   `json:"priority,omitempty"`
+	Read      bool       `json:"read,omitempty"`
+	CreatedAt Timestamp  `json:"createTime"`
+	ReadAt    *Timestamp `json:"readTime,omitempty"`
+}
+
+// Sender represents the application that sent a notification.
+type Sender struct {
+	ClientID string `json:"clientId,omitempty"`
+	Name     string `json:"name,omitempty"`
+}
+
+// Priority is the priority of a notification.
+type Priority int
+
+const (
+	// PriorityLow represents a notification that can be read at leisure.
+	PriorityLow Priority = 1
+	// PriorityNormal represents an ordinary notification.
+	PriorityNormal Priority = 2
+	// PriorityHigh represents an urgent notification.
+	PriorityHigh Priority = 3
+)
+
+// ListOptions specifies the pagination of list methods.
+type ListOptions struct {
+	// NextToken is the token returned in the Response of the previous page.
+	NextToken string `url:"nextToken,omitempty"`
+	Limit     int    `url:"limit,omitempty"`
+}
+
+// NotificationListOptions specifies the optional parameters to the
+// NotificationService.List method.
+type NotificationListOptions struct {
+	// Read filters notifications by read state if set.
+	Read *bool `url:"read,omitempty"`
+
+	// Since and Until filter notifications by the time they were sent.
+	Since Timestamp `url:"beginTime,omitempty"`
+	Until Timestamp `url:"endTime,omitempty"`
+
+	ListOptions
+}
+
+// List returns a page of the notifications of the user. The NextToken of the
+// returned Response is empty on the last page.
+//
+// It requires the notifications scope.
+func (s *NotificationService) List(ctx context.Context, opts *NotificationListOptions) ([]*Notification, *Response, error) {
+	if err := s.client.checkScopes(ScopeNotifications); err != nil {
+		return nil, nil, err
+	}
+
+	values, err := query.Values(opts)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	req, err := s.client.NewRequest(http.MethodGet, "/v1/me/notifications", values)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var notifications []*Notification
+	response, _, err := s.client.do(ctx, req, &notifications)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return notifications, response, nil
+}
+
+// Get returns a single notification.
+//
+// It requires the notifications scope.
+func (s *NotificationService) Get(ctx context.Context, id string) (*Notification, error) {
+	if err := s.client.checkScopes(ScopeNotifications); err != nil {
+		return nil, err
+	}
+
+	req, err := s.client.NewRequest(http.MethodGet, fmt.Sprintf("/v1/me/notifications/%s", url.PathEscape(id)), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	notification := make([]Notification, 1)
+	_, err = s.client.Do(ctx, req, &notification)
+	if err != nil {
+		return nil, err
+	}
+	if len(notification) == 0 {
+		return nil, ErrNoEntities
+	}
+
+	return &notification[0], nil
+}
+
+// MarkRead marks a notification as read.
+//
+// It requires the modify_notification scope.
+func (s *NotificationService) MarkRead(ctx context.Context, id string) error {
+	return s.modify(ctx, http.MethodPut, fmt.Sprintf("/v1/me/notifications/%s/read", url.PathEscape(id)))
+}
+
+// MarkUnread marks a notification as unread.
+//
+// It requires the modify_notification scope.
+func (s *NotificationService) MarkUnread(ctx context.Context, id string) error {
+	return s.modify(ctx, http.MethodDelete, fmt.Sprintf("/v1/me/notifications/%s/read", url.PathEscape(id)))
+}
+
+// Delete deletes a notification.
+//
+// It requires the modify_notification scope.
+func (s *NotificationService) Delete(ctx context.Context, id string) error {
+	return s.modify(ctx, http.MethodDelete, fmt.Sprintf("/v1/me/notifications/%s", url.PathEscape(id)))
+}
+
+func (s *NotificationService) modify(ctx context.Context, method string, path string) error {
+	if err := s.client.checkScopes(ScopeModifyNotification); err != nil {
+		return err
+	}
+
+	req, err := s.client.NewRequest(method, path, nil)
+	if err != nil {
+		return err
+	}
+
+	_, err = s.client.Do(ctx, req, nil)
+	return err
+}
@@ -0,0 +1,212 @@
+/*
+Copyright 2021 The Go jAccount Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package jaccount
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"net/http"
+	"sort"
+	"text/template"
+)
+
+// maxRecipients is the maximum number of recipients of a single send request.
+const maxRecipients = 100
+
+// NotificationRequest represents a notification to be sent.
+type NotificationRequest struct {
+	Title    string   `json:"title"`
+	Content  string   `json:"content"`
+	URL      string   `json:"url,omitempty"`
+	Priority Priority `json:"priority,omitempty"`
+
+	// Accounts are the jAccount accounts of the recipients.
+	Accounts []string `json:"accounts"`
+}
+
+// SendFailure reports a recipient the notification could not be sent to.
+type SendFailure struct {
+	Account string `json:"account"`
+	Reason  string `json:"error,omitempty"`
+}
+
+// SendResult reports the outcome of sending a notification.
+type SendResult struct {
+	// Sent lists the recipients the notification was sent to.
+	Sent []string
+
+	// Failures lists the recipients the notification could not be sent
+	// to, including those of batches that failed as a whole.
+	Failures []*SendFailure
+}
+
+// Send sends a notification to the accounts of the request. Large recipient
+// lists are split into batches; a failure of one batch does not stop the
+// others, and is reported for each of its recipients in the result. The
+// returned error is only non-nil if nothing was attempted, such as when there
+// are no recipients.
+//
+// It requires the send_notification scope.
+func (s *NotificationService) Send(ctx context.Context, n *NotificationRequest) (*SendResult, error) {
+	if err := s.client.checkScopes(ScopeSendNotification); err != nil {
+		return nil, err
+	}
+	if len(n.Accounts) == 0 {
+		return nil, errors.New("no recipients")
+	}
+
+	result := &SendResult{}
+	for start := 0; start < len(n.Accounts); start += maxRecipients {
+		end := start + maxRecipients
+		if end > len(n.Accounts) {
+			end = len(n.Accounts)
+		}
+
+		batch := *n
+		batch.Accounts = n.Accounts[start:end]
+		s.sendBatch(ctx, &batch, result)
+	}
+
+	return result, nil
+}
+
+func (s *NotificationService) sendBatch(ctx context.Context, n *NotificationRequest, result *SendResult) {
+	failBatch := func(err error) {
+		for _, account := range n.Accounts {
+			result.Failures = append(result.Failures, &SendFailure{Account: account, Reason: err.Error()})
+		}
+	}
+
+	if err := ctx.Err(); err != nil {
+		failBatch(err)
+		return
+	}
+
+	req, err := s.client.NewJSONRequest(http.MethodPost, "/v1/notifications", n)
+	if err != nil {
+		failBatch(err)
+		return
+	}
+
+	var failures []*SendFailure
+	_, err = s.client.Do(ctx, req, &failures)
+	if err != nil {
+		failBatch(err)
+		return
+	}
+
+	failed := make(map[string]bool, len(failures))
+	for _, f := range failures {
+		failed[f.Account] = true
+	}
+	for _, account := range n.Accounts {
+		if !failed[account] {
+			result.Sent = append(result.Sent, account)
+		}
+	}
+	result.Failures = append(result.Failures, failures...)
+}
+
+// NotificationTemplate renders personalized notifications with text/template.
+type NotificationTemplate struct {
+	Title    *template.Template
+	Content  *template.Template
+	URL      string
+	Priority Priority
+}
+
+// ParseNotificationTemplate parses the templates of the title and content.
+func ParseNotificationTemplate(title string, content string) (*NotificationTemplate, error) {
+	titleTmpl, err := template.New("title").Option("missingkey=error").Parse(title)
+	if err != nil {
+		return nil, err
+	}
+	contentTmpl, err := template.New("content").Option("missingkey=error").Parse(content)
+	if err != nil {
+		return nil, err
+	}
+	return &NotificationTemplate{Title: titleTmpl, Content: contentTmpl}, nil
+}
+
+// Render renders the notification for the data of one recipient.
+func (t *NotificationTemplate) Render(data interface{}) (*NotificationRequest, error) {
+	var title, content bytes.Buffer
+	if err := t.Title.Execute(&title, data); err != nil {
+		return nil, err
+	}
+	if err := t.Content.Execute(&content, data); err != nil {
+		return nil, err
+	}
+
+	return &NotificationRequest{
+		Title:    title.String(),
+		Content:  content.String(),
+		URL:      t.URL,
+		Priority: t.Priority,
+	}, nil
+}
+
+// SendTemplate renders the template for every recipient with their data,
+// keyed by account, and sends the notifications. Recipients whose rendered
+// notifications are identical are sent in the same batch. Recipients whose
+// notification fails to render are reported as failures.
+//
+// It requires the send_notification scope.
+func (s *NotificationService) SendTemplate(ctx context.Context, t *NotificationTemplate, recipients map[string]interface{}) (*SendResult, error) {
+	if err := s.client.checkScopes(ScopeSendNotification); err != nil {
+		return nil, err
+	}
+
+	accounts := make([]string, 0, len(recipients))
+	for account := range recipients {
+		accounts = append(accounts, account)
+	}
+	sort.Strings(accounts)
+
+	type key struct{ title, content string }
+	var (
+		order    []key
+		rendered = make(map[key]*NotificationRequest)
+		result   = &SendResult{}
+	)
+	for _, account := range accounts {
+		n, err := t.Render(recipients[account])
+		if err != nil {
+			result.Failures = append(result.Failures, &SendFailure{Account: account, Reason: err.Error()})
+			continue
+		}
+
+		k := key{n.Title, n.Content}
+		if _, ok := rendered[k]; !ok {
+			rendered[k] = n
+			order = append(order, k)
+		}
+		rendered[k].Accounts = append(rendered[k].Accounts, account)
+	}
+
+	for _, k := range order {
+		sent, err := s.Send(ctx, rendered[k])
+		if err != nil {
+			return nil, err
+		}
+		result.Sent = append(result.Sent, sent.Sent...)
+		result.Failures = append(result.Failures, sent.Failures...)
+	}
+
+	return result, nil
+}
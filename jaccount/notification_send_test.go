@@ -0,0 +1,131 @@
+/*
+Copyright 2021 The Go jAccount Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package jaccount
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"reflect"
+	"sort"
+	"testing"
+)
+
+func TestNotificationService_Send(t *testing.T) {
+	client, mux, teardown := setup(t)
+	defer teardown()
+
+	batches := 0
+	mux.HandleFunc("/v1/notifications", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, http.MethodPost)
+		batches++
+
+		var n NotificationRequest
+		if err := json.NewDecoder(r.Body).Decode(&n); err != nil {
+			t.Errorf("error = %v", err)
+		}
+		if len(n.Accounts) > maxRecipients {
+			t.Errorf("batch of %d recipients, want at most %d", len(n.Accounts), maxRecipients)
+		}
+
+		// The second batch fails as a whole, and user000 is unknown.
+		if batches == 2 {
+			w.WriteHeader(http.StatusInternalServerError)
+			w.Write([]byte(`{"errno":500,"error":"internal error"}`))
+			return
+		}
+		var failures []*SendFailure
+		for _, account := range n.Accounts {
+			if account == "user000" {
+				failures = append(failures, &SendFailure{Account: account, Reason: "account not found"})
+			}
+		}
+		writeResponse(t, w, failures, 0, "")
+	})
+
+	accounts := make([]string, 250)
+	for i := range accounts {
+		accounts[i] = fmt.Sprintf("user%03d", i)
+	}
+
+	result, err := client.Notifications.Send(context.Background(), &NotificationRequest{
+		Title:    "作业提醒",
+		Content:  "作业将于明天截止",
+		Priority: PriorityHigh,
+		Accounts: accounts,
+	})
+	if err != nil {
+		t.Fatalf("NotificationService.Send() error = %v", err)
+	}
+
+	if batches != 3 {
+		t.Errorf("NotificationService.Send() sent %d batches, want 3", batches)
+	}
+	if len(result.Sent) != 149 {
+		t.Errorf("NotificationService.Send() sent to %d recipients, want 149", len(result.Sent))
+	}
+	if len(result.Failures) != 101 || result.Failures[0].Account != "user000" || result.Failures[0].Reason != "account not found" {
+		t.Errorf("NotificationService.Send() failures = %d, first %+v", len(result.Failures), result.Failures[0])
+	}
+}
+
+func TestNotificationService_SendTemplate(t *testing.T) {
+	client, mux, teardown := setup(t)
+	defer teardown()
+
+	var got []NotificationRequest
+	mux.HandleFunc("/v1/notifications", func(w http.ResponseWriter, r *http.Request) {
+		var n NotificationRequest
+		if err := json.NewDecoder(r.Body).Decode(&n); err != nil {
+			t.Errorf("error = %v", err)
+		}
+		got = append(got, n)
+		writeResponse(t, w, []*SendFailure{}, 0, "")
+	})
+
+	tmpl, err := ParseNotificationTemplate("{{.Course}} 作业提醒", "{{.Assignment}} 将于 {{.Due}} 截止")
+	if err != nil {
+		t.Fatalf("ParseNotificationTemplate() error = %v", err)
+	}
+
+	result, err := client.Notifications.SendTemplate(context.Background(), tmpl, map[string]interface{}{
+		"alice": map[string]string{"Course": "SE101", "Assignment": "Lab 1", "Due": "周五"},
+		"bob":   map[string]string{"Course": "SE101", "Assignment": "Lab 1", "Due": "周五"},
+		"carol": map[string]string{"Course": "SE102", "Assignment": "Lab 2", "Due": "周六"},
+		"dave":  map[string]string{"Course": "SE102"},
+	})
+	if err != nil {
+		t.Fatalf("NotificationService.SendTemplate() error = %v", err)
+	}
+
+	want := []NotificationRequest{
+		{Title: "SE101 作业提醒", Content: "Lab 1 将于 周五 截止", Accounts: []string{"alice", "bob"}},
+		{Title: "SE102 作业提醒", Content: "Lab 2 将于 周六 截止", Accounts: []string{"carol"}},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("NotificationService.SendTemplate() sent %+v, want %+v", got, want)
+	}
+
+	sort.Strings(result.Sent)
+	if !reflect.DeepEqual(result.Sent, []string{"alice", "bob", "carol"}) {
+		t.Errorf("NotificationService.SendTemplate() Sent = %v", result.Sent)
+	}
+	if len(result.Failures) != 1 || result.Failures[0].Account != "dave" {
+		t.Errorf("NotificationService.SendTemplate() Failures = %+v", result.Failures)
+	}
+}
@@ -0,0 +1,149 @@
+/*
+Copyright 2021 The Go jAccount Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package jaccount
+
+import (
+	"context"
+	"net/http"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestNotificationService_List(t *testing.T) {
+	client, mux, teardown := setup(t)
+	defer teardown()
+
+	notifications := []*Notification{
+		{ID: "2", Title: "作业提醒", Priority: PriorityHigh, CreatedAt: NewTimestamp(time.Unix(1700000000, 0))},
+		{ID: "1", Title: "通知", Read: true, CreatedAt: NewTimestamp(time.Unix(1600000000, 0)), ReadAt: &Timestamp{time.Unix(1600000100, 0)}},
+	}
+
+	mux.HandleFunc("/v1/me/notifications", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, http.MethodGet)
+
+		q := r.URL.Query()
+		if q.Get("read") != "false" || q.Get("beginTime") != "1600000000000" || q.Get("limit") != "1" {
+			t.Errorf("unexpected query %v", q)
+		}
+
+		switch q.Get("nextToken") {
+		case "":
+			writeResponse(t, w, notifications[:1], 2, "page2")
+		case "page2":
+			writeResponse(t, w, notifications[1:], 2, "")
+		}
+	})
+
+	read := false
+	opts := &NotificationListOptions{
+		Read:        &read,
+		Since:       NewTimestamp(time.Unix(1600000000, 0)),
+		ListOptions: ListOptions{Limit: 1},
+	}
+
+	var got []*Notification
+	for {
+		page, resp, err := client.Notifications.List(context.Background(), opts)
+		if err != nil {
+			t.Fatalf("NotificationService.List() error = %v", err)
+		}
+		if resp.Total != 2 {
+			t.Errorf("NotificationService.List() Total = %d, want 2", resp.Total)
+		}
+
+		got = append(got, page...)
+		if resp.NextToken == "" {
+			break
+		}
+		opts.NextToken = resp.NextToken
+	}
+
+	if len(got) != len(notifications) {
+		t.Fatalf("NotificationService.List() returned %d notifications, want %d", len(got), len(notifications))
+	}
+	for i := range got {
+		if got[i].ID != notifications[i].ID || !got[i].CreatedAt.Equal(notifications[i].CreatedAt.Time) {
+			t.Errorf("NotificationService.List()[%d] = %+v, want %+v", i, got[i], notifications[i])
+		}
+	}
+	if got[1].ReadAt == nil || !got[1].ReadAt.Equal(time.Unix(1600000100, 0)) {
+		t.Errorf("NotificationService.List()[1].ReadAt = %v", got[1].ReadAt)
+	}
+}
+
+func TestNotificationService_Get(t *testing.T) {
+	client, mux, teardown := setup(t)
+	defer teardown()
+
+	notification := &Notification{ID: "a/b", Title: "通知", Sender: &Sender{Name: "Canvas"}}
+	mux.HandleFunc("/v1/me/notifications/", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, http.MethodGet)
+		if r.URL.EscapedPath() != "/v1/me/notifications/a%2Fb" {
+			t.Errorf("Request path = %v", r.URL.EscapedPath())
+		}
+		writeResponse(t, w, []*Notification{notification}, 0, "")
+	})
+
+	got, err := client.Notifications.Get(context.Background(), "a/b")
+	if err != nil {
+		t.Fatalf("NotificationService.Get() error = %v", err)
+	}
+	if !reflect.DeepEqual(got, notification) {
+		t.Errorf("NotificationService.Get() = %+v, want %+v", got, notification)
+	}
+}
+
+func TestNotificationService_modify(t *testing.T) {
+	client, mux, teardown := setup(t)
+	defer teardown()
+
+	var requests []string
+	mux.HandleFunc("/v1/me/notifications/", func(w http.ResponseWriter, r *http.Request) {
+		requests = append(requests, r.Method+" "+r.URL.Path)
+		if r.URL.Path == "/v1/me/notifications/missing" {
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(`{"errno":404,"error":"notification not found"}`))
+			return
+		}
+		w.Write([]byte(`{"errno":0,"error":"success"}`))
+	})
+
+	ctx := context.Background()
+	if err := client.Notifications.MarkRead(ctx, "1"); err != nil {
+		t.Errorf("NotificationService.MarkRead() error = %v", err)
+	}
+	if err := client.Notifications.MarkUnread(ctx, "1"); err != nil {
+		t.Errorf("NotificationService.MarkUnread() error = %v", err)
+	}
+	if err := client.Notifications.Delete(ctx, "1"); err != nil {
+		t.Errorf("NotificationService.Delete() error = %v", err)
+	}
+	if err := client.Notifications.Delete(ctx, "missing"); err == nil {
+		t.Errorf("NotificationService.Delete() error = nil, want error")
+	}
+
+	want := []string{
+		"PUT /v1/me/notifications/1/read",
+		"DELETE /v1/me/notifications/1/read",
+		"DELETE /v1/me/notifications/1",
+		"DELETE /v1/me/notifications/missing",
+	}
+	if !reflect.DeepEqual(requests, want) {
+		t.Errorf("requests = %v, want %v", requests, want)
+	}
+}
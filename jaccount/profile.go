@@ -86,7 +86,14 @@ type Major struct {
 }
 
 // Get gets the profile of the user.
+//
+// It requires the basic scope. Fields such as Identities are only filled in
+// when the essential scope is granted too.
 func (s *ProfileService) Get(ctx context.Context) (*Profile, error) {
+	if err := s.client.checkScopes(ScopeBasic); err != nil {
+		return nil, err
+	}
+
 	req, err := s.client.NewRequest(http.MethodGet, "/v1/me/profile", nil)
 	if err != nil {
 		return nil, err
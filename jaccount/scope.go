@@ -0,0 +1,206 @@
+/*
+Copyright 2021 The Go jAccount Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package jaccount
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"golang.org/x/oauth2"
+)
+
+// Scopes is a set of OAuth 2.0 scopes.
+type Scopes map[string]struct{}
+
+// NewScopes returns a set containing the given scopes.
+func NewScopes(scopes ...string) Scopes {
+	s := make(Scopes, len(scopes))
+	for _, scope := range scopes {
+		if scope != "" {
+			s[scope] = struct{}{}
+		}
+	}
+	return s
+}
+
+// ParseScopes parses a scope string as returned by the token endpoint. Scopes
+// may be separated by spaces or commas.
+func ParseScopes(str string) Scopes {
+	return NewScopes(strings.FieldsFunc(str, func(r rune) bool {
+		return r == ' ' || r == ','
+	})...)
+}
+
+// TokenScopes returns the scopes granted to the token, or nil if the token
+// endpoint did not report them.
+func TokenScopes(t *oauth2.Token) Scopes {
+	if t == nil {
+		return nil
+	}
+
+	switch scope := t.Extra("scope").(type) {
+	case string:
+		return ParseScopes(scope)
+	case []interface{}:
+		s := make(Scopes, len(scope))
+		for _, v := range scope {
+			if str, ok := v.(string); ok {
+				s[str] = struct{}{}
+			}
+		}
+		return s
+	default:
+		return nil
+	}
+}
+
+// Contains reports whether all the given scopes are in the set.
+func (s Scopes) Contains(scopes ...string) bool {
+	for _, scope := range scopes {
+		if _, ok := s[scope]; !ok {
+			return false
+		}
+	}
+	return true
+}
+
+// Union returns a new set containing the scopes of both sets.
+func (s Scopes) Union(other Scopes) Scopes {
+	u := make(Scopes, len(s)+len(other))
+	for scope := range s {
+		u[scope] = struct{}{}
+	}
+	for scope := range other {
+		u[scope] = struct{}{}
+	}
+	return u
+}
+
+// Missing returns the sorted scopes of required that are not in the set.
+func (s Scopes) Missing(required Scopes) []string {
+	var missing []string
+	for scope := range required {
+		if _, ok := s[scope]; !ok {
+			missing = append(missing, scope)
+		}
+	}
+	sort.Strings(missing)
+	return missing
+}
+
+// List returns the sorted scopes of the set, suitable for oauth2.Config.
+func (s Scopes) List() []string {
+	list := make([]string, 0, len(s))
+	for scope := range s {
+		list = append(list, scope)
+	}
+	sort.Strings(list)
+	return list
+}
+
+// String returns the space-separated form of the set.
+func (s Scopes) String() string {
+	return strings.Join(s.List(), " ")
+}
+
+// Validate returns an error if the set contains a scope unknown to jAccount.
+func (s Scopes) Validate() error {
+	unknown := knownScopes.Missing(s)
+	if len(unknown) > 0 {
+		return fmt.Errorf("unknown scopes: %s", strings.Join(unknown, " "))
+	}
+	return nil
+}
+
+var knownScopes = NewScopes(
+	ScopeOpenID,
+	ScopeBasic,
+	ScopeEssential,
+	ScopeProfile,
+	ScopeTasks,
+	ScopeNotifications,
+	ScopePrivacy,
+	ScopeIntrospect,
+	ScopeReadApps,
+	ScopeWriteApps,
+	ScopeExchangeData,
+	ScopeSendNotification,
+	ScopeReadMails,
+	ScopeSendMail,
+	ScopeStorage,
+	ScopeModifyNotification,
+	ScopeLessons,
+	ScopeClasses,
+	ScopeExams,
+	ScopeScores,
+	ScopeStudentList,
+	ScopeCardInfo,
+	ScopeCardTransactions,
+	ScopeWriteCardInfo,
+	ScopeIncome,
+	ScopeCreateJAccount,
+	ScopeEditJAccount,
+	ScopeNetServiceInfo,
+	ScopeConnectWechat,
+	ScopeConnectShmec,
+	ScopePrint,
+	ScopeConnectFinance,
+	ScopeStudentAffairs,
+)
+
+// InsufficientScopeError is returned when the token of the client has not
+// been granted the scopes required by an API method.
+type InsufficientScopeError struct {
+	Required Scopes
+	Granted  Scopes
+	Missing  []string
+}
+
+func (e *InsufficientScopeError) Error() string {
+	return fmt.Sprintf("insufficient scope: missing %s", strings.Join(e.Missing, " "))
+}
+
+// checkScopes returns an InsufficientScopeError if the token of the client is
+// known to lack any of the required scopes. Nothing is checked when the
+// client has no token source or the token does not report its scopes.
+func (c *Client) checkScopes(required ...string) error {
+	if c.TokenSource == nil {
+		return nil
+	}
+
+	token, err := c.TokenSource.Token()
+	if err != nil {
+		return err
+	}
+
+	granted := TokenScopes(token)
+	if granted == nil {
+		return nil
+	}
+
+	req := NewScopes(required...)
+	if missing := granted.Missing(req); len(missing) > 0 {
+		return &InsufficientScopeError{
+			Required: req,
+			Granted:  granted,
+			Missing:  missing,
+		}
+	}
+
+	return nil
+}
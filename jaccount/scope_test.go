@@ -0,0 +1,116 @@
+/*
+Copyright 2021 The Go jAccount Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package jaccount
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"reflect"
+	"testing"
+
+	"golang.org/x/oauth2"
+)
+
+func TestParseScopes(t *testing.T) {
+	tests := []struct {
+		name string
+		str  string
+		want string
+	}{
+		{"empty", "", ""},
+		{"space separated", "essential basic", "basic essential"},
+		{"comma separated", "essential,basic, lessons", "basic essential lessons"},
+		{"duplicated", "basic basic", "basic"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ParseScopes(tt.str).String(); got != tt.want {
+				t.Errorf("ParseScopes() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestScopes(t *testing.T) {
+	granted := NewScopes(ScopeBasic, ScopeEssential)
+	required := NewScopes(ScopeEssential, ScopeCardInfo, ScopeCardTransactions)
+
+	if !granted.Contains(ScopeBasic, ScopeEssential) {
+		t.Errorf("Scopes.Contains() = false, want true")
+	}
+	if granted.Contains(ScopeBasic, ScopeLessons) {
+		t.Errorf("Scopes.Contains() = true, want false")
+	}
+
+	want := []string{ScopeCardInfo, ScopeCardTransactions}
+	if got := granted.Missing(required); !reflect.DeepEqual(got, want) {
+		t.Errorf("Scopes.Missing() = %v, want %v", got, want)
+	}
+
+	if got := granted.Union(required).String(); got != "basic card_info card_transactions essential" {
+		t.Errorf("Scopes.Union() = %q", got)
+	}
+
+	if err := granted.Validate(); err != nil {
+		t.Errorf("Scopes.Validate() error = %v", err)
+	}
+	if err := NewScopes(ScopeBasic, "unknown").Validate(); err == nil {
+		t.Errorf("Scopes.Validate() error = nil, want error")
+	}
+}
+
+func TestClient_checkScopes(t *testing.T) {
+	token := &oauth2.Token{AccessToken: "token"}
+
+	tests := []struct {
+		name    string
+		token   *oauth2.Token
+		wantErr bool
+	}{
+		{"granted", token.WithExtra(map[string]interface{}{"scope": "basic card_info"}), false},
+		{"unreported", token, false},
+		{"insufficient", token.WithExtra(map[string]interface{}{"scope": "basic"}), true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := &oauth2.Config{}
+			client := NewClient(config.Client(context.Background(), tt.token))
+			if client.TokenSource == nil {
+				t.Fatalf("NewClient() did not set TokenSource")
+			}
+
+			err := client.checkScopes(ScopeCardInfo)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Client.checkScopes() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+
+			var scopeErr *InsufficientScopeError
+			if tt.wantErr && (!errors.As(err, &scopeErr) || !reflect.DeepEqual(scopeErr.Missing, []string{ScopeCardInfo})) {
+				t.Errorf("Client.checkScopes() error = %v, want missing %v", err, ScopeCardInfo)
+			}
+		})
+	}
+
+	client := NewClient(&http.Client{})
+	if err := client.checkScopes(ScopeCardInfo); err != nil {
+		t.Errorf("Client.checkScopes() error = %v, want nil without token source", err)
+	}
+}
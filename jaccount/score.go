@@ -0,0 +1,88 @@
+/*
+Copyright 2021 The Go jAccount Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package jaccount
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/google/go-querystring/query"
+)
+
+// ScoresService handles communications with the course score related methods
+// of the jAccount API.
+type ScoresService service
+
+// Score represents the score of the user in a course.
+type Score struct {
+	CourseCode string  `json:"courseCode,omitempty"`
+	CourseName string  `json:"courseName,omitempty"`
+	Term       Term    `json:"term"`
+	Credits    float64 `json:"credits,omitempty"`
+
+	// Grade is a letter grade such as A-, a pass/fail grade P or F, or a
+	// percentage score such as 92.
+	Grade string `json:"grade,omitempty"`
+
+	// GradePoint is the grade point reported by the registrar.
+	GradePoint float64 `json:"gradePoint,omitempty"`
+
+	// ExamType is the kind of exam the score is from, such as 期末考试 or
+	// 补考.
+	ExamType string `json:"examType,omitempty"`
+
+	// Retake reports whether the course was taken again after failing or
+	// to improve the grade.
+	Retake bool `json:"retake,omitempty"`
+
+	// PassFail reports whether the course is graded pass/fail.
+	PassFail bool `json:"passFail,omitempty"`
+}
+
+// ScoreListOptions specifies the optional parameters to the
+// ScoresService.List method.
+type ScoreListOptions struct {
+	// Term filters scores by term if set.
+	Term Term `url:"term,omitempty"`
+}
+
+// List returns the course scores of the user.
+//
+// It requires the scores scope.
+func (s *ScoresService) List(ctx context.Context, opts *ScoreListOptions) ([]*Score, error) {
+	if err := s.client.checkScopes(ScopeScores); err != nil {
+		return nil, err
+	}
+
+	values, err := query.Values(opts)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := s.client.NewRequest(http.MethodGet, "/v1/me/scores", values)
+	if err != nil {
+		return nil, err
+	}
+
+	var scores []*Score
+	_, err = s.client.Do(ctx, req, &scores)
+	if err != nil {
+		return nil, err
+	}
+
+	return scores, nil
+}
@@ -0,0 +1,47 @@
+/*
+Copyright 2021 The Go jAccount Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package jaccount
+
+import (
+	"context"
+	"net/http"
+	"testing"
+)
+
+func TestScoresService_List(t *testing.T) {
+	client, mux, teardown := setup(t)
+	defer teardown()
+
+	mux.HandleFunc("/v1/me/scores", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, http.MethodGet)
+		if got := r.URL.Query().Get("term"); got != "2025-2026-2" {
+			t.Errorf("term = %q, want %q", got, "2025-2026-2")
+		}
+		writeResponse(t, w, []*Score{
+			{CourseCode: "MA1201", Term: Term{2025, Spring}, Credits: 6, Grade: "A-", GradePoint: 3.7},
+			{CourseCode: "PE001", Term: Term{2025, Spring}, Credits: 1, Grade: "P", PassFail: true},
+		}, 2, "")
+	})
+
+	scores, err := client.Scores.List(context.Background(), &ScoreListOptions{Term: Term{2025, Spring}})
+	if err != nil {
+		t.Fatalf("ScoresService.List() error = %v", err)
+	}
+	if len(scores) != 2 || scores[0].Grade != "A-" || !scores[1].PassFail {
+		t.Errorf("ScoresService.List() = %+v", scores)
+	}
+}
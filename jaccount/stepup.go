@@ -0,0 +1,231 @@
+/*
+Copyright 2021 The Go jAccount Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package jaccount
+
+import (
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"golang.org/x/oauth2"
+)
+
+const (
+	defaultStepUpCookieName = "jaccount_step_up"
+	stepUpCookieMaxAge      = 10 * time.Minute
+)
+
+// StepUp implements incremental authorization, which requests additional
+// scopes from a user who has already logged in. The user's existing session
+// is left untouched; only the stored token is replaced by the merged token
+// returned from Callback.
+type StepUp struct {
+	// Config is the OAuth 2.0 configuration of the application. Its
+	// RedirectURL must point to the handler calling Callback.
+	Config *oauth2.Config
+
+	// CookieName is the name of the cookie that keeps the state of the
+	// step-up between Start and Callback. Defaults to "jaccount_step_up".
+	CookieName string
+
+	// DefaultReturnURL is used when the return URL is missing or unsafe.
+	// Defaults to "/".
+	DefaultReturnURL string
+}
+
+// AuthCodeURL returns the URL of the consent page that requests the scopes
+// already granted to the token together with the additional scopes.
+func (s *StepUp) AuthCodeURL(state string, granted *oauth2.Token, scopes ...string) string {
+	return s.config(s.requestedScopes(granted, scopes)).AuthCodeURL(state)
+}
+
+// Start redirects the user to the consent page for the additional scopes.
+// After the user grants them, Callback sends the user back to returnURL,
+// typically the page that triggered the step-up.
+func (s *StepUp) Start(w http.ResponseWriter, r *http.Request, granted *oauth2.Token, returnURL string, scopes ...string) {
+	state := randomString(16)
+	requested := s.requestedScopes(granted, scopes)
+
+	values := url.Values{}
+	values.Set("state", state)
+	values.Set("scope", requested.String())
+	values.Set("return", returnURL)
+
+	http.SetCookie(w, &http.Cookie{
+		Name:     s.cookieName(),
+		Value:    base64.RawURLEncoding.EncodeToString([]byte(values.Encode())),
+		Path:     "/",
+		MaxAge:   int(stepUpCookieMaxAge.Seconds()),
+		Secure:   r.TLS != nil,
+		HttpOnly: true,
+		SameSite: http.SameSiteLaxMode,
+	})
+
+	http.Redirect(w, r, s.config(requested).AuthCodeURL(state), http.StatusFound)
+}
+
+// Callback completes the step-up. It exchanges the authorization code, merges
+// the newly issued token into stored and returns the merged token together
+// with the URL the user should be sent back to.
+func (s *StepUp) Callback(w http.ResponseWriter, r *http.Request, stored *oauth2.Token) (*oauth2.Token, string, error) {
+	cookie, err := r.Cookie(s.cookieName())
+	if err != nil {
+		return nil, "", errors.New("step-up state not found")
+	}
+
+	http.SetCookie(w, &http.Cookie{
+		Name:     s.cookieName(),
+		Path:     "/",
+		MaxAge:   -1,
+		Secure:   r.TLS != nil,
+		HttpOnly: true,
+	})
+
+	raw, err := base64.RawURLEncoding.DecodeString(cookie.Value)
+	if err != nil {
+		return nil, "", errors.New("malformed step-up state")
+	}
+	values, err := url.ParseQuery(string(raw))
+	if err != nil {
+		return nil, "", errors.New("malformed step-up state")
+	}
+
+	query := r.URL.Query()
+	if query.Get("state") == "" || query.Get("state") != values.Get("state") {
+		return nil, "", errors.New("state mismatch")
+	}
+
+	returnURL := SafeReturnURL(values.Get("return"), s.defaultReturnURL())
+
+	if e := query.Get("error"); e != "" {
+		return nil, returnURL, fmt.Errorf("step-up authorization failed: %s", e)
+	}
+
+	requested := ParseScopes(values.Get("scope"))
+	issued, err := s.config(requested).Exchange(r.Context(), query.Get("code"))
+	if err != nil {
+		return nil, returnURL, err
+	}
+
+	if TokenScopes(issued) == nil {
+		issued = withScopes(issued, requested)
+	}
+
+	return MergeToken(stored, issued), returnURL, nil
+}
+
+func (s *StepUp) requestedScopes(granted *oauth2.Token, scopes []string) Scopes {
+	current := TokenScopes(granted)
+	if current == nil {
+		current = NewScopes(s.Config.Scopes...)
+	}
+	return current.Union(NewScopes(scopes...))
+}
+
+func (s *StepUp) config(scopes Scopes) *oauth2.Config {
+	c := *s.Config
+	c.Scopes = scopes.List()
+	return &c
+}
+
+func (s *StepUp) cookieName() string {
+	if s.CookieName == "" {
+		return defaultStepUpCookieName
+	}
+	return s.CookieName
+}
+
+func (s *StepUp) defaultReturnURL() string {
+	if s.DefaultReturnURL == "" {
+		return "/"
+	}
+	return s.DefaultReturnURL
+}
+
+// MergeToken merges a token issued by a step-up authorization into the stored
+// token. The issued token wins, but the refresh token and ID token of stored
+// are kept if the issued token lacks them, and the granted scopes are the
+// union of both.
+func MergeToken(stored, issued *oauth2.Token) *oauth2.Token {
+	if stored == nil {
+		return issued
+	}
+
+	merged := *issued
+	if merged.RefreshToken == "" {
+		merged.RefreshToken = stored.RefreshToken
+	}
+
+	extra := map[string]interface{}{}
+	if scopes := TokenScopes(issued); scopes != nil {
+		extra["scope"] = scopes.Union(TokenScopes(stored)).String()
+	} else if scopes := TokenScopes(stored); scopes != nil {
+		extra["scope"] = scopes.String()
+	}
+
+	if idToken, ok := issued.Extra("id_token").(string); ok {
+		extra["id_token"] = idToken
+	} else if idToken, ok := stored.Extra("id_token").(string); ok {
+		extra["id_token"] = idToken
+	}
+
+	return merged.WithExtra(extra)
+}
+
+// SafeReturnURL returns raw if it is a local path that is safe to redirect
+// to, or fallback otherwise. It rejects absolute and protocol-relative URLs
+// to prevent open redirects.
+func SafeReturnURL(raw string, fallback string) string {
+	if raw == "" || !strings.HasPrefix(raw, "/") || strings.HasPrefix(raw, "//") || strings.HasPrefix(raw, "/\\") {
+		return fallback
+	}
+
+	u, err := url.Parse(raw)
+	if err != nil || u.Scheme != "" || u.Host != "" {
+		return fallback
+	}
+
+	for _, r := range raw {
+		if r < 0x20 || r == 0x7f || r == '\\' {
+			return fallback
+		}
+	}
+
+	return raw
+}
+
+func withScopes(t *oauth2.Token, scopes Scopes) *oauth2.Token {
+	extra := map[string]interface{}{"scope": scopes.String()}
+	if idToken, ok := t.Extra("id_token").(string); ok {
+		extra["id_token"] = idToken
+	}
+	return t.WithExtra(extra)
+}
+
+// randomString returns a URL-safe random string encoding n random bytes.
+func randomString(n int) string {
+	b := make([]byte, n)
+	if _, err := rand.Read(b); err != nil {
+		panic(err)
+	}
+	return base64.RawURLEncoding.EncodeToString(b)
+}
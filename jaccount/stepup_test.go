@@ -0,0 +1,148 @@
+/*
+Copyright 2021 The Go jAccount Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package jaccount
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"golang.org/x/oauth2"
+)
+
+func TestSafeReturnURL(t *testing.T) {
+	tests := []struct {
+		name string
+		raw  string
+		want string
+	}{
+		{"empty", "", "/"},
+		{"local path", "/card/transactions?page=2", "/card/transactions?page=2"},
+		{"absolute URL", "https://evil.example.com/", "/"},
+		{"protocol-relative URL", "//evil.example.com/", "/"},
+		{"backslash", "/\\evil.example.com/", "/"},
+		{"relative path", "card", "/"},
+		{"control character", "/\r\nLocation: x", "/"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := SafeReturnURL(tt.raw, "/"); got != tt.want {
+				t.Errorf("SafeReturnURL() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMergeToken(t *testing.T) {
+	stored := (&oauth2.Token{AccessToken: "old", RefreshToken: "refresh"}).WithExtra(map[string]interface{}{
+		"scope":    "basic",
+		"id_token": "id",
+	})
+	issued := (&oauth2.Token{AccessToken: "new"}).WithExtra(map[string]interface{}{
+		"scope": "card_transactions",
+	})
+
+	merged := MergeToken(stored, issued)
+	if merged.AccessToken != "new" {
+		t.Errorf("MergeToken().AccessToken = %q, want %q", merged.AccessToken, "new")
+	}
+	if merged.RefreshToken != "refresh" {
+		t.Errorf("MergeToken().RefreshToken = %q, want %q", merged.RefreshToken, "refresh")
+	}
+	if got := TokenScopes(merged).String(); got != "basic card_transactions" {
+		t.Errorf("MergeToken() scopes = %q, want %q", got, "basic card_transactions")
+	}
+	if got := merged.Extra("id_token"); got != "id" {
+		t.Errorf("MergeToken() id_token = %v, want %q", got, "id")
+	}
+}
+
+func TestStepUp(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.PostFormValue("code") != "code" {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"access_token":"new","token_type":"bearer","expires_in":3600}`))
+	}))
+	defer ts.Close()
+
+	stepUp := &StepUp{
+		Config: &oauth2.Config{
+			ClientID:    "client",
+			Endpoint:    oauth2.Endpoint{AuthURL: ts.URL + "/authorize", TokenURL: ts.URL + "/token"},
+			RedirectURL: "http://localhost/callback",
+			Scopes:      []string{ScopeBasic},
+		},
+	}
+	stored := &oauth2.Token{AccessToken: "old", RefreshToken: "refresh"}
+
+	rec := httptest.NewRecorder()
+	stepUp.Start(rec, httptest.NewRequest(http.MethodGet, "/card", nil), stored, "/card", ScopeCardTransactions)
+
+	location, err := url.Parse(rec.Header().Get("Location"))
+	if err != nil {
+		t.Fatalf("error = %v", err)
+	}
+	if got := location.Query().Get("scope"); got != "basic card_transactions" {
+		t.Errorf("StepUp.Start() scope = %q, want %q", got, "basic card_transactions")
+	}
+
+	cookies := rec.Result().Cookies()
+	if len(cookies) != 1 {
+		t.Fatalf("StepUp.Start() set %d cookies, want 1", len(cookies))
+	}
+
+	tests := []struct {
+		name       string
+		query      url.Values
+		wantReturn string
+		wantErr    bool
+	}{
+		{"success", url.Values{"state": {location.Query().Get("state")}, "code": {"code"}}, "/card", false},
+		{"state mismatch", url.Values{"state": {"forged"}, "code": {"code"}}, "", true},
+		{"access denied", url.Values{"state": {location.Query().Get("state")}, "error": {"access_denied"}}, "/card", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/callback?"+tt.query.Encode(), nil)
+			req.AddCookie(cookies[0])
+
+			got, returnURL, err := stepUp.Callback(httptest.NewRecorder(), req, stored)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("StepUp.Callback() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if returnURL != tt.wantReturn {
+				t.Errorf("StepUp.Callback() returnURL = %q, want %q", returnURL, tt.wantReturn)
+			}
+			if tt.wantErr {
+				return
+			}
+			if got.AccessToken != "new" || got.RefreshToken != "refresh" {
+				t.Errorf("StepUp.Callback() token = %v", got)
+			}
+			if scopes := TokenScopes(got).String(); scopes != "basic card_transactions" {
+				t.Errorf("StepUp.Callback() scopes = %q, want %q", scopes, "basic card_transactions")
+			}
+		})
+	}
+}
@@ -0,0 +1,239 @@
+/*
+Copyright 2021 The Go jAccount Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package jaccount
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/url"
+	"path"
+	"strings"
+
+	"github.com/google/go-querystring/query"
+)
+
+// StorageService handles communications with the jBox cloud storage related
+// methods of the jAccount API. Paths are slash-separated and relative to the
+// root of the storage of the user.
+type StorageService service
+
+// ErrStorageRoot is returned by methods that would delete, move or overwrite
+// the root of the storage, as addressed by an empty path or "/".
+var ErrStorageRoot = errors.New("operation not allowed on the storage root")
+
+// FileInfo describes a file or directory in the storage.
+type FileInfo struct {
+	Path    string    `json:"path,omitempty"`
+	Name    string    `json:"name,omitempty"`
+	IsDir   bool      `json:"isDir,omitempty"`
+	Size    int64     `json:"size,omitempty"`
+	ModTime Timestamp `json:"modifyTime"`
+
+	// SHA256 is the hex-encoded SHA-256 checksum of the content of a file.
+	SHA256 string `json:"sha256,omitempty"`
+}
+
+// List returns a page of the entries of the directory.
+//
+// It requires the storage scope.
+func (s *StorageService) List(ctx context.Context, dir string, opts *ListOptions) ([]*FileInfo, *Response, error) {
+	if err := s.client.checkScopes(ScopeStorage); err != nil {
+		return nil, nil, err
+	}
+
+	values, err := query.Values(opts)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	req, err := s.client.NewRequest(http.MethodGet, storagePath("/v1/me/storage/list", dir), values)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var files []*FileInfo
+	response, _, err := s.client.do(ctx, req, &files)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return files, response, nil
+}
+
+// Stat returns the description of the file or directory.
+//
+// It requires the storage scope.
+func (s *StorageService) Stat(ctx context.Context, name string) (*FileInfo, error) {
+	if err := s.client.checkScopes(ScopeStorage); err != nil {
+		return nil, err
+	}
+
+	req, err := s.client.NewRequest(http.MethodGet, storagePath("/v1/me/storage/metadata", name), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.doFile(ctx, req)
+}
+
+// Mkdir creates the directory along with any missing parents.
+//
+// It requires the storage scope.
+func (s *StorageService) Mkdir(ctx context.Context, dir string) (*FileInfo, error) {
+	if err := s.client.checkScopes(ScopeStorage); err != nil {
+		return nil, err
+	}
+
+	req, err := s.client.NewRequest(http.MethodPost, storagePath("/v1/me/storage/folders", dir), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.doFile(ctx, req)
+}
+
+// Move moves the file or directory from src to dst.
+//
+// It requires the storage scope.
+func (s *StorageService) Move(ctx context.Context, src string, dst string, overwrite bool) (*FileInfo, error) {
+	return s.transfer(ctx, "/v1/me/storage/move", src, dst, overwrite)
+}
+
+// Copy copies the file or directory from src to dst.
+//
+// It requires the storage scope.
+func (s *StorageService) Copy(ctx context.Context, src string, dst string, overwrite bool) (*FileInfo, error) {
+	return s.transfer(ctx, "/v1/me/storage/copy", src, dst, overwrite)
+}
+
+func (s *StorageService) transfer(ctx context.Context, endpoint string, src string, dst string, overwrite bool) (*FileInfo, error) {
+	if err := s.client.checkScopes(ScopeStorage); err != nil {
+		return nil, err
+	}
+	if isStorageRoot(src) || isStorageRoot(dst) {
+		return nil, ErrStorageRoot
+	}
+
+	body := struct {
+		From      string `json:"from"`
+		To        string `json:"to"`
+		Overwrite bool   `json:"overwrite,omitempty"`
+	}{cleanStoragePath(src), cleanStoragePath(dst), overwrite}
+	req, err := s.client.NewJSONRequest(http.MethodPost, endpoint, body)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.doFile(ctx, req)
+}
+
+// Delete deletes the file, or the directory and everything in it.
+//
+// It requires the storage scope.
+func (s *StorageService) Delete(ctx context.Context, name string) error {
+	if err := s.client.checkScopes(ScopeStorage); err != nil {
+		return err
+	}
+	if isStorageRoot(name) {
+		return ErrStorageRoot
+	}
+
+	req, err := s.client.NewRequest(http.MethodDelete, storagePath("/v1/me/storage/files", name), nil)
+	if err != nil {
+		return err
+	}
+
+	_, err = s.client.Do(ctx, req, nil)
+	return err
+}
+
+// Download returns the content of the file. The caller must close the
+// returned reader.
+//
+// It requires the storage scope.
+func (s *StorageService) Download(ctx context.Context, name string) (io.ReadCloser, error) {
+	if err := s.client.checkScopes(ScopeStorage); err != nil {
+		return nil, err
+	}
+
+	req, err := s.client.NewRequest(http.MethodGet, storagePath("/v1/me/storage/files", name), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	body, _, err := s.client.doStream(ctx, req)
+	return body, err
+}
+
+// Upload uploads size bytes read from r to the file in a single request. Use
+// UploadChunked for large files.
+//
+// It requires the storage scope.
+func (s *StorageService) Upload(ctx context.Context, name string, r io.Reader, size int64, overwrite bool) (*FileInfo, error) {
+	if err := s.client.checkScopes(ScopeStorage); err != nil {
+		return nil, err
+	}
+	if isStorageRoot(name) {
+		return nil, ErrStorageRoot
+	}
+
+	req, err := s.client.NewUploadRequest(http.MethodPut, storagePath("/v1/me/storage/files", name), r, size, "application/octet-stream")
+	if err != nil {
+		return nil, err
+	}
+	if overwrite {
+		req.URL.RawQuery = url.Values{"overwrite": {"true"}}.Encode()
+	}
+
+	return s.doFile(ctx, req)
+}
+
+func (s *StorageService) doFile(ctx context.Context, req *http.Request) (*FileInfo, error) {
+	file := make([]FileInfo, 1)
+	_, err := s.client.Do(ctx, req, &file)
+	if err != nil {
+		return nil, err
+	}
+	if len(file) == 0 {
+		return nil, ErrNoEntities
+	}
+
+	return &file[0], nil
+}
+
+// cleanStoragePath returns the canonical form of a storage path, which is
+// absolute and has no trailing slash.
+func cleanStoragePath(name string) string {
+	return path.Clean("/" + name)
+}
+
+// isStorageRoot reports whether name addresses the root of the storage.
+func isStorageRoot(name string) bool {
+	return cleanStoragePath(name) == "/"
+}
+
+// storagePath returns the path of an endpoint addressing a storage path, with
+// every segment escaped.
+func storagePath(endpoint string, name string) string {
+	segments := strings.Split(strings.TrimPrefix(cleanStoragePath(name), "/"), "/")
+	for i, segment := range segments {
+		segments[i] = url.PathEscape(segment)
+	}
+	return endpoint + "/" + strings.Join(segments, "/")
+}
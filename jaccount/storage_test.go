@@ -0,0 +1,193 @@
+/*
+Copyright 2021 The Go jAccount Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package jaccount
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"strconv"
+	"testing"
+)
+
+func TestStorageService(t *testing.T) {
+	client, mux, teardown := setup(t)
+	defer teardown()
+
+	files := map[string][]byte{}
+	fileInfo := func(name string) *FileInfo {
+		sum := sha256.Sum256(files[name])
+		return &FileInfo{Path: name, Size: int64(len(files[name])), SHA256: hex.EncodeToString(sum[:])}
+	}
+
+	mux.HandleFunc("/v1/me/storage/files/", func(w http.ResponseWriter, r *http.Request) {
+		name := r.URL.Path[len("/v1/me/storage/files"):]
+		switch r.Method {
+		case http.MethodPut:
+			files[name], _ = ioutil.ReadAll(r.Body)
+			writeResponse(t, w, []*FileInfo{fileInfo(name)}, 0, "")
+		case http.MethodGet:
+			w.Header().Set("Content-Type", "application/octet-stream")
+			w.Write(files[name])
+		case http.MethodDelete:
+			delete(files, name)
+			writeResponse(t, w, nil, 0, "")
+		}
+	})
+	mux.HandleFunc("/v1/me/storage/move", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, http.MethodPost)
+		var body struct{ From, To string }
+		json.NewDecoder(r.Body).Decode(&body)
+		files[body.To] = files[body.From]
+		delete(files, body.From)
+		writeResponse(t, w, []*FileInfo{fileInfo(body.To)}, 0, "")
+	})
+	mux.HandleFunc("/v1/me/storage/list/data", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, http.MethodGet)
+		var list []*FileInfo
+		for name := range files {
+			list = append(list, fileInfo(name))
+		}
+		writeResponse(t, w, list, len(list), "")
+	})
+
+	ctx := context.Background()
+	content := []byte("temperature,pressure\n")
+	file, err := client.Storage.Upload(ctx, "data/实验 1.csv", bytes.NewReader(content), int64(len(content)), false)
+	if err != nil || file.Path != "/data/实验 1.csv" || file.Size != int64(len(content)) {
+		t.Fatalf("StorageService.Upload() = %+v, %v", file, err)
+	}
+
+	if _, err := client.Storage.Move(ctx, "data/实验 1.csv", "/data/run1.csv", false); err != nil {
+		t.Fatalf("StorageService.Move() error = %v", err)
+	}
+
+	list, _, err := client.Storage.List(ctx, "/data/", nil)
+	if err != nil || len(list) != 1 || list[0].Path != "/data/run1.csv" {
+		t.Errorf("StorageService.List() = %v, %v", list, err)
+	}
+
+	body, err := client.Storage.Download(ctx, "/data/run1.csv")
+	if err != nil {
+		t.Fatalf("StorageService.Download() error = %v", err)
+	}
+	got, _ := ioutil.ReadAll(body)
+	body.Close()
+	if !bytes.Equal(got, content) {
+		t.Errorf("StorageService.Download() = %q, want %q", got, content)
+	}
+
+	if err := client.Storage.Delete(ctx, "/data/run1.csv"); err != nil || len(files) != 0 {
+		t.Errorf("StorageService.Delete() error = %v, files = %v", err, files)
+	}
+
+	for _, root := range []string{"", "/", ".", "data/.."} {
+		if err := client.Storage.Delete(ctx, root); !errors.Is(err, ErrStorageRoot) {
+			t.Errorf("StorageService.Delete(%q) error = %v, want %v", root, err, ErrStorageRoot)
+		}
+	}
+}
+
+func TestStorageService_UploadChunked(t *testing.T) {
+	client, mux, teardown := setup(t)
+	defer teardown()
+
+	content := bytes.Repeat([]byte("0123456789"), 10)
+	sum := sha256.Sum256(content)
+	session := &UploadSession{ID: "u1", Path: "/big.bin", Size: int64(len(content)), SHA256: hex.EncodeToString(sum[:]), ChunkSize: 30}
+
+	var (
+		received []byte
+		chunks   int
+		failAt   = 2
+		corrupt  bool
+	)
+	mux.HandleFunc("/v1/me/storage/uploads", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, http.MethodPost)
+		writeResponse(t, w, []*UploadSession{session}, 0, "")
+	})
+	mux.HandleFunc("/v1/me/storage/uploads/u1", func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodGet:
+			writeResponse(t, w, []*UploadSession{session}, 0, "")
+		case http.MethodPut:
+			chunks++
+			if chunks == failAt {
+				w.WriteHeader(http.StatusServiceUnavailable)
+				json.NewEncoder(w).Encode(&Response{ErrNO: 503, Error: "unavailable"})
+				return
+			}
+			if got := r.URL.Query().Get("offset"); got != strconv.Itoa(len(received)) {
+				t.Errorf("offset = %s, want %d", got, len(received))
+			}
+			chunk, _ := ioutil.ReadAll(r.Body)
+			received = append(received, chunk...)
+			session.Offset = int64(len(received))
+			writeResponse(t, w, []*UploadSession{session}, 0, "")
+		}
+	})
+	mux.HandleFunc("/v1/me/storage/uploads/u1/complete", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, http.MethodPost)
+		sum := sha256.Sum256(received)
+		if corrupt {
+			sum[0]++
+		}
+		writeResponse(t, w, []*FileInfo{{Path: "/big.bin", Size: int64(len(received)), SHA256: hex.EncodeToString(sum[:])}}, 0, "")
+	})
+
+	ctx := context.Background()
+	r := bytes.NewReader(content)
+	var progress []int64
+	opts := &UploadOptions{Progress: func(uploaded, total int64) { progress = append(progress, uploaded) }}
+
+	_, err := client.Storage.UploadChunked(ctx, "big.bin", r, int64(len(content)), opts)
+	var uploadErr *UploadError
+	if !errors.As(err, &uploadErr) || uploadErr.SessionID != "u1" || uploadErr.Offset != 30 {
+		t.Fatalf("StorageService.UploadChunked() error = %v, want UploadError at 30", err)
+	}
+
+	opts.SessionID = uploadErr.SessionID
+	sent := chunks
+	if _, err := client.Storage.UploadChunked(ctx, "other.bin", r, int64(len(content)), opts); err == nil || chunks != sent {
+		t.Fatalf("StorageService.UploadChunked() resumed to another file: error = %v, %d chunks sent", err, chunks-sent)
+	}
+
+	file, err := client.Storage.UploadChunked(ctx, "big.bin", r, int64(len(content)), opts)
+	if err != nil {
+		t.Fatalf("StorageService.UploadChunked() resume error = %v", err)
+	}
+	if file.Size != int64(len(content)) || !bytes.Equal(received, content) {
+		t.Errorf("StorageService.UploadChunked() = %+v, received %q", file, received)
+	}
+	if want := []int64{30, 60, 90, 100}; len(progress) != len(want) || progress[3] != 100 {
+		t.Errorf("progress = %v, want %v", progress, want)
+	}
+
+	corrupt = true
+	received = nil
+	session.Offset = 0
+	_, err = client.Storage.UploadChunked(ctx, "big.bin", r, int64(len(content)), &UploadOptions{})
+	var checksumErr *ChecksumError
+	if !errors.As(err, &checksumErr) {
+		t.Errorf("StorageService.UploadChunked() error = %v, want ChecksumError", err)
+	}
+}
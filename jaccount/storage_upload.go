@@ -0,0 +1,238 @@
+/*
+Copyright 2021 The Go jAccount Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package jaccount
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+)
+
+// DefaultChunkSize is the chunk size of UploadChunked unless specified.
+const DefaultChunkSize = 8 << 20
+
+// UploadSession represents a resumable chunked upload.
+type UploadSession struct {
+	ID        string    `json:"id,omitempty"`
+	Path      string    `json:"path,omitempty"`
+	Size      int64     `json:"size,omitempty"`
+	SHA256    string    `json:"sha256,omitempty"`
+	ChunkSize int64     `json:"chunkSize,omitempty"`
+	ExpiresAt Timestamp `json:"expireTime"`
+
+	// Offset is the number of bytes received by the server so far.
+	Offset int64 `json:"offset"`
+}
+
+// UploadOptions specifies the optional parameters to the
+// StorageService.UploadChunked method.
+type UploadOptions struct {
+	// ChunkSize defaults to DefaultChunkSize. The server may choose a
+	// different size.
+	ChunkSize int64
+
+	// Overwrite replaces an existing file.
+	Overwrite bool
+
+	// Progress, if set, is called after each chunk with the number of bytes
+	// received by the server and the size of the file.
+	Progress func(uploaded int64, total int64)
+
+	// SessionID resumes an interrupted upload, as reported by an
+	// UploadError.
+	SessionID string
+}
+
+// UploadError is returned by UploadChunked when an upload is interrupted. It
+// can be resumed by passing SessionID in UploadOptions.
+type UploadError struct {
+	SessionID string
+	Offset    int64
+	Err       error
+}
+
+func (e *UploadError) Error() string {
+	return fmt.Sprintf("upload %s interrupted at offset %d: %v", e.SessionID, e.Offset, e.Err)
+}
+
+func (e *UploadError) Unwrap() error {
+	return e.Err
+}
+
+// ChecksumError is returned when the checksum of an uploaded file computed by
+// the server does not match the local content.
+type ChecksumError struct {
+	Path     string
+	Expected string
+	Actual   string
+}
+
+func (e *ChecksumError) Error() string {
+	return fmt.Sprintf("checksum mismatch for %s: expected sha256 %s, got %s", e.Path, e.Expected, e.Actual)
+}
+
+// UploadChunked uploads size bytes read from r to the file in chunks, so that
+// an interrupted upload of a large file can be resumed. The SHA-256 checksum
+// of the content is computed up front and verified against the stored file.
+// A resumed session must be an upload to the same file.
+//
+// It requires the storage scope.
+func (s *StorageService) UploadChunked(ctx context.Context, name string, r io.ReaderAt, size int64, opts *UploadOptions) (*FileInfo, error) {
+	if err := s.client.checkScopes(ScopeStorage); err != nil {
+		return nil, err
+	}
+	if isStorageRoot(name) {
+		return nil, ErrStorageRoot
+	}
+
+	if opts == nil {
+		opts = &UploadOptions{}
+	}
+
+	h := sha256.New()
+	if _, err := io.Copy(h, io.NewSectionReader(r, 0, size)); err != nil {
+		return nil, err
+	}
+	sum := hex.EncodeToString(h.Sum(nil))
+
+	var (
+		session *UploadSession
+		err     error
+	)
+	if opts.SessionID != "" {
+		session, err = s.getUploadSession(ctx, opts.SessionID)
+		if err != nil {
+			return nil, err
+		}
+		if session.Path != cleanStoragePath(name) {
+			return nil, fmt.Errorf("upload %s is to %s, not %s", session.ID, session.Path, cleanStoragePath(name))
+		}
+		if session.Size != size || session.SHA256 != sum {
+			return nil, errors.New("content changed since the upload was started")
+		}
+	} else {
+		session, err = s.createUploadSession(ctx, name, size, sum, opts)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	chunkSize := session.ChunkSize
+	if chunkSize <= 0 {
+		chunkSize = opts.ChunkSize
+	}
+	if chunkSize <= 0 {
+		chunkSize = DefaultChunkSize
+	}
+
+	for session.Offset < size {
+		n := chunkSize
+		if remaining := size - session.Offset; remaining < n {
+			n = remaining
+		}
+
+		next, err := s.uploadChunk(ctx, session.ID, session.Offset, io.NewSectionReader(r, session.Offset, n), n)
+		if err != nil {
+			return nil, &UploadError{SessionID: session.ID, Offset: session.Offset, Err: err}
+		}
+		if next.Offset <= session.Offset {
+			return nil, &UploadError{SessionID: session.ID, Offset: session.Offset, Err: errors.New("server made no progress")}
+		}
+		session.Offset = next.Offset
+
+		if opts.Progress != nil {
+			opts.Progress(session.Offset, size)
+		}
+	}
+
+	file, err := s.completeUpload(ctx, session.ID)
+	if err != nil {
+		return nil, &UploadError{SessionID: session.ID, Offset: session.Offset, Err: err}
+	}
+	if file.SHA256 != sum {
+		return nil, &ChecksumError{Path: file.Path, Expected: sum, Actual: file.SHA256}
+	}
+
+	return file, nil
+}
+
+func (s *StorageService) createUploadSession(ctx context.Context, name string, size int64, sum string, opts *UploadOptions) (*UploadSession, error) {
+	body := struct {
+		Path      string `json:"path"`
+		Size      int64  `json:"size"`
+		SHA256    string `json:"sha256"`
+		ChunkSize int64  `json:"chunkSize,omitempty"`
+		Overwrite bool   `json:"overwrite,omitempty"`
+	}{cleanStoragePath(name), size, sum, opts.ChunkSize, opts.Overwrite}
+	req, err := s.client.NewJSONRequest(http.MethodPost, "/v1/me/storage/uploads", body)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.doUploadSession(ctx, req)
+}
+
+func (s *StorageService) getUploadSession(ctx context.Context, id string) (*UploadSession, error) {
+	req, err := s.client.NewRequest(http.MethodGet, uploadPath(id), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.doUploadSession(ctx, req)
+}
+
+func (s *StorageService) uploadChunk(ctx context.Context, id string, offset int64, chunk io.Reader, size int64) (*UploadSession, error) {
+	req, err := s.client.NewUploadRequest(http.MethodPut, uploadPath(id), chunk, size, "application/octet-stream")
+	if err != nil {
+		return nil, err
+	}
+	req.URL.RawQuery = url.Values{"offset": {strconv.FormatInt(offset, 10)}}.Encode()
+
+	return s.doUploadSession(ctx, req)
+}
+
+func (s *StorageService) completeUpload(ctx context.Context, id string) (*FileInfo, error) {
+	req, err := s.client.NewRequest(http.MethodPost, uploadPath(id)+"/complete", nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.doFile(ctx, req)
+}
+
+func (s *StorageService) doUploadSession(ctx context.Context, req *http.Request) (*UploadSession, error) {
+	session := make([]UploadSession, 1)
+	_, err := s.client.Do(ctx, req, &session)
+	if err != nil {
+		return nil, err
+	}
+	if len(session) == 0 {
+		return nil, ErrNoEntities
+	}
+
+	return &session[0], nil
+}
+
+func uploadPath(id string) string {
+	return fmt.Sprintf("/v1/me/storage/uploads/%s", url.PathEscape(id))
+}
@@ -0,0 +1,147 @@
+/*
+Copyright 2021 The Go jAccount Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package jaccount
+
+import (
+	"context"
+	"encoding/csv"
+	"io"
+	"net/http"
+
+	"github.com/google/go-querystring/query"
+)
+
+// StudentListService handles communications with the administrative student
+// query methods of the jAccount API.
+type StudentListService service
+
+// StudentListOptions specifies the filters and pagination of the
+// StudentListService.List method. Filters that are set must all match.
+type StudentListOptions struct {
+	// Organize is the ID of the organization, such as a school.
+	Organize string `url:"organizeId,omitempty"`
+
+	// Major is the ID of the major.
+	Major string `url:"majorId,omitempty"`
+
+	// ClassNO is the administrative class number, as in Profile.ClassNO.
+	ClassNO string `url:"classNo,omitempty"`
+
+	// AdmissionYear is the year the students were admitted in.
+	AdmissionYear int `url:"admissionYear,omitempty"`
+
+	ListOptions
+}
+
+// List returns a page of the students matching the filters.
+//
+// It requires the student_list scope.
+func (s *StudentListService) List(ctx context.Context, opts *StudentListOptions) ([]*Profile, *Response, error) {
+	if err := s.client.checkScopes(ScopeStudentList); err != nil {
+		return nil, nil, err
+	}
+
+	values, err := query.Values(opts)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	req, err := s.client.NewRequest(http.MethodGet, "/v1/students", values)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var students []*Profile
+	response, _, err := s.client.do(ctx, req, &students)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return students, response, nil
+}
+
+// studentCSVHeader is the header row written by ExportCSV.
+var studentCSVHeader = []string{
+	"account", "name", "code", "user_type", "organize_id", "organize", "class_no", "email",
+	"identity_kind", "identity_status", "major_id", "major", "admission_date",
+}
+
+// ExportCSV writes every student matching the filters to w as CSV and returns
+// the number of students written. Pages are written as they arrive, so the
+// export never holds more than one page in memory. The identity columns
+// describe the default identity of each student.
+//
+// It requires the student_list scope.
+func (s *StudentListService) ExportCSV(ctx context.Context, w io.Writer, opts *StudentListOptions) (int, error) {
+	page := StudentListOptions{}
+	if opts != nil {
+		page = *opts
+	}
+
+	cw := csv.NewWriter(w)
+	if err := cw.Write(studentCSVHeader); err != nil {
+		return 0, err
+	}
+
+	n := 0
+	for {
+		students, response, err := s.List(ctx, &page)
+		if err != nil {
+			return n, err
+		}
+
+		for _, p := range students {
+			if err := cw.Write(studentRecord(p)); err != nil {
+				return n, err
+			}
+			n++
+		}
+		cw.Flush()
+		if err := cw.Error(); err != nil {
+			return n, err
+		}
+
+		if response.NextToken == "" || len(students) == 0 {
+			return n, nil
+		}
+		page.NextToken = response.NextToken
+	}
+}
+
+func studentRecord(p *Profile) []string {
+	var organizeID, organize string
+	if p.Organize != nil {
+		organizeID, organize = p.Organize.ID, p.Organize.Name
+	}
+
+	var kind, status, majorID, major, admission string
+	for _, identity := range p.Identities {
+		if identity == nil || !identity.IsDefault {
+			continue
+		}
+		kind, status, admission = identity.Kind, identity.Status, identity.AdmissionDate
+		if identity.Major != nil {
+			majorID, major = identity.Major.ID, identity.Major.Name
+		}
+		break
+	}
+
+	return []string{
+		p.Account, p.Name, p.Code, p.UserType, organizeID, organize, p.ClassNO, p.Email,
+		kind, status, majorID, major, admission,
+	}
+}
@@ -0,0 +1,71 @@
+/*
+Copyright 2021 The Go jAccount Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package jaccount
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"testing"
+)
+
+func TestStudentListService_ExportCSV(t *testing.T) {
+	client, mux, teardown := setup(t)
+	defer teardown()
+
+	students := []*Profile{
+		{
+			Account:  "alice",
+			Name:     "Alice",
+			Code:     "526030910001",
+			Organize: &Organize{ID: "03000", Name: "电子信息与电气工程学院"},
+			ClassNO:  "F2603001",
+			Identities: []*Identity{
+				{Kind: "graduate", Status: "正常"},
+				nil,
+				{Kind: "undergraduate", IsDefault: true, Status: "正常", Major: &Major{ID: "080901", Name: "计算机科学与技术"}, AdmissionDate: "2026-09-01"},
+			},
+		},
+		{Account: "bob", Name: "Bob, Jr.", Code: "526030910002", ClassNO: "F2603001"},
+	}
+
+	mux.HandleFunc("/v1/students", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, http.MethodGet)
+		q := r.URL.Query()
+		if q.Get("classNo") != "F2603001" || q.Get("admissionYear") != "2026" {
+			t.Errorf("query = %v", q)
+		}
+		if q.Get("nextToken") == "" {
+			writeResponse(t, w, students[:1], 2, "p2")
+		} else {
+			writeResponse(t, w, students[1:], 2, "")
+		}
+	})
+
+	var buf bytes.Buffer
+	n, err := client.StudentList.ExportCSV(context.Background(), &buf, &StudentListOptions{ClassNO: "F2603001", AdmissionYear: 2026})
+	if err != nil || n != 2 {
+		t.Fatalf("StudentListService.ExportCSV() = %d, %v, want 2", n, err)
+	}
+
+	want := "account,name,code,user_type,organize_id,organize,class_no,email,identity_kind,identity_status,major_id,major,admission_date\n" +
+		"alice,Alice,526030910001,,03000,电子信息与电气工程学院,F2603001,,undergraduate,正常,080901,计算机科学与技术,2026-09-01\n" +
+		"bob,\"Bob, Jr.\",526030910002,,,,F2603001,,,,,,\n"
+	if got := buf.String(); got != want {
+		t.Errorf("StudentListService.ExportCSV() wrote\n%s\nwant\n%s", got, want)
+	}
+}
@@ -0,0 +1,162 @@
+/*
+Copyright 2021 The Go jAccount Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package jaccount
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+
+	"github.com/google/go-querystring/query"
+)
+
+// TaskService handles communications with the to-do task related methods of
+// the jAccount API.
+type TaskService service
+
+// Task represents a to-do task in the task list of the user.
+type Task struct {
+	ID          string     `json:"id,omitempty"`
+	Title       string     `json:"title,omitempty"`
+	Description string     `json:"description,omitempty"`
+	URL         string     `json:"url,omitempty"`
+	Due         *Timestamp `json:"dueTime,omitempty"`
+	Completed   bool       `json:"completed,omitempty"`
+	CompletedAt *Timestamp `json:"completeTime,omitempty"`
+	CreatedAt   *Timestamp `json:"createTime,omitempty"`
+	UpdatedAt   *Timestamp `json:"updateTime,omitempty"`
+}
+
+// TaskListOptions specifies the optional parameters to the TaskService.List
+// method.
+type TaskListOptions struct {
+	// Completed filters tasks by completion if set.
+	Completed *bool `url:"completed,omitempty"`
+
+	// DueAfter and DueBefore filter tasks by due time.
+	DueAfter  Timestamp `url:"dueBegin,omitempty"`
+	DueBefore Timestamp `url:"dueEnd,omitempty"`
+
+	ListOptions
+}
+
+// List returns a page of the tasks of the user.
+//
+// It requires the tasks scope.
+func (s *TaskService) List(ctx context.Context, opts *TaskListOptions) ([]*Task, *Response, error) {
+	if err := s.client.checkScopes(ScopeTasks); err != nil {
+		return nil, nil, err
+	}
+
+	values, err := query.Values(opts)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	req, err := s.client.NewRequest(http.MethodGet, "/v1/me/tasks", values)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var tasks []*Task
+	response, _, err := s.client.do(ctx, req, &tasks)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return tasks, response, nil
+}
+
+// Create creates a task and returns it as stored.
+//
+// It requires the tasks scope.
+func (s *TaskService) Create(ctx context.Context, task *Task) (*Task, error) {
+	return s.send(ctx, http.MethodPost, "/v1/me/tasks", task)
+}
+
+// Update updates the task with the given ID and returns it as stored.
+//
+// It requires the tasks scope.
+func (s *TaskService) Update(ctx context.Context, id string, task *Task) (*Task, error) {
+	return s.send(ctx, http.MethodPut, taskPath(id), task)
+}
+
+// Complete marks the task as completed.
+//
+// It requires the tasks scope.
+func (s *TaskService) Complete(ctx context.Context, id string) (*Task, error) {
+	return s.send(ctx, http.MethodPut, taskPath(id)+"/completed", nil)
+}
+
+// Reopen marks the completed task as not completed.
+//
+// It requires the tasks scope.
+func (s *TaskService) Reopen(ctx context.Context, id string) (*Task, error) {
+	return s.send(ctx, http.MethodDelete, taskPath(id)+"/completed", nil)
+}
+
+// Delete deletes the task.
+//
+// It requires the tasks scope.
+func (s *TaskService) Delete(ctx context.Context, id string) error {
+	if err := s.client.checkScopes(ScopeTasks); err != nil {
+		return err
+	}
+
+	req, err := s.client.NewRequest(http.MethodDelete, taskPath(id), nil)
+	if err != nil {
+		return err
+	}
+
+	_, err = s.client.Do(ctx, req, nil)
+	return err
+}
+
+func (s *TaskService) send(ctx context.Context, method string, path string, body *Task) (*Task, error) {
+	if err := s.client.checkScopes(ScopeTasks); err != nil {
+		return nil, err
+	}
+
+	var (
+		req *http.Request
+		err error
+	)
+	if body != nil {
+		req, err = s.client.NewJSONRequest(method, path, body)
+	} else {
+		req, err = s.client.NewRequest(method, path, nil)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	task := make([]Task, 1)
+	_, err = s.client.Do(ctx, req, &task)
+	if err != nil {
+		return nil, err
+	}
+	if len(task) == 0 {
+		return nil, ErrNoEntities
+	}
+
+	return &task[0], nil
+}
+
+func taskPath(id string) string {
+	return fmt.Sprintf("/v1/me/tasks/%s", url.PathEscape(id))
+}
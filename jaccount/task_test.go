@@ -0,0 +1,101 @@
+/*
+Copyright 2021 The Go jAccount Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package jaccount
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestTaskService(t *testing.T) {
+	client, mux, teardown := setup(t)
+	defer teardown()
+
+	due := time.Date(2026, 10, 23, 23, 59, 0, 0, time.UTC)
+	stored := map[string]*Task{}
+
+	mux.HandleFunc("/v1/me/tasks", func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodGet:
+			if got := r.URL.Query().Get("completed"); got != "false" {
+				t.Errorf("completed = %q, want %q", got, "false")
+			}
+			var tasks []*Task
+			for _, task := range stored {
+				if !task.Completed {
+					tasks = append(tasks, task)
+				}
+			}
+			writeResponse(t, w, tasks, len(tasks), "")
+		case http.MethodPost:
+			task := new(Task)
+			if err := json.NewDecoder(r.Body).Decode(task); err != nil {
+				t.Errorf("error = %v", err)
+			}
+			task.ID = "1"
+			stored[task.ID] = task
+			writeResponse(t, w, []*Task{task}, 0, "")
+		}
+	})
+	mux.HandleFunc("/v1/me/tasks/1/completed", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, http.MethodPut)
+		stored["1"].Completed = true
+		writeResponse(t, w, []*Task{stored["1"]}, 0, "")
+	})
+
+	ctx := context.Background()
+	created, err := client.Tasks.Create(ctx, &Task{Title: "Lab 1", Due: &Timestamp{due}})
+	if err != nil {
+		t.Fatalf("TaskService.Create() error = %v", err)
+	}
+	if created.ID != "1" || created.Due == nil || !created.Due.Equal(due) {
+		t.Errorf("TaskService.Create() = %+v", created)
+	}
+
+	completed := false
+	tasks, _, err := client.Tasks.List(ctx, &TaskListOptions{Completed: &completed})
+	if err != nil || len(tasks) != 1 {
+		t.Fatalf("TaskService.List() = %v, %v, want 1 task", tasks, err)
+	}
+
+	task, err := client.Tasks.Complete(ctx, "1")
+	if err != nil || !task.Completed {
+		t.Errorf("TaskService.Complete() = %+v, %v", task, err)
+	}
+
+	tasks, _, err = client.Tasks.List(ctx, &TaskListOptions{Completed: &completed})
+	if err != nil || len(tasks) != 0 {
+		t.Errorf("TaskService.List() = %v, %v, want no tasks", tasks, err)
+	}
+}
+
+func TestTaskService_noEntities(t *testing.T) {
+	client, mux, teardown := setup(t)
+	defer teardown()
+
+	mux.HandleFunc("/v1/me/tasks/1/completed", func(w http.ResponseWriter, r *http.Request) {
+		writeResponse(t, w, []*Task{}, 0, "")
+	})
+
+	if _, err := client.Tasks.Complete(context.Background(), "1"); !errors.Is(err, ErrNoEntities) {
+		t.Errorf("TaskService.Complete() error = %v, want %v", err, ErrNoEntities)
+	}
+}
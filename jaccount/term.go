@@ -0,0 +1,108 @@
+/*
+Copyright 2021 The Go jAccount Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package jaccount
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// Semester is a semester of an academic year.
+type Semester int
+
+const (
+	// Fall is the first semester, starting in September.
+	Fall Semester = 1
+	// Spring is the second semester, starting in February or March.
+	Spring Semester = 2
+	// Summer is the short summer semester.
+	Summer Semester = 3
+)
+
+// Term is a semester of an academic year, written as 2026-2027-1 for the fall
+// semester of the academic year starting in 2026.
+type Term struct {
+	// Year is the year the academic year starts in.
+	Year     int
+	Semester Semester
+}
+
+// ErrNoTerm is returned by methods that require a term when given the zero
+// Term.
+var ErrNoTerm = errors.New("term not set")
+
+// ParseTerm parses a term written as 2026-2027-1.
+func ParseTerm(s string) (Term, error) {
+	parts := strings.Split(s, "-")
+	if len(parts) != 3 {
+		return Term{}, fmt.Errorf("malformed term %q", s)
+	}
+
+	start, err1 := strconv.Atoi(parts[0])
+	end, err2 := strconv.Atoi(parts[1])
+	semester, err3 := strconv.Atoi(parts[2])
+	if err1 != nil || err2 != nil || err3 != nil || end != start+1 || semester < 1 || semester > 3 {
+		return Term{}, fmt.Errorf("malformed term %q", s)
+	}
+
+	return Term{Year: start, Semester: Semester(semester)}, nil
+}
+
+// String returns the term written as 2026-2027-1.
+func (t Term) String() string {
+	return fmt.Sprintf("%d-%d-%d", t.Year, t.Year+1, t.Semester)
+}
+
+// IsZero reports whether t is the zero Term.
+func (t Term) IsZero() bool {
+	return t == Term{}
+}
+
+// EncodeValues implements query.Encoder. The zero Term is omitted.
+func (t Term) EncodeValues(key string, v *url.Values) error {
+	if !t.IsZero() {
+		v.Set(key, t.String())
+	}
+	return nil
+}
+
+// MarshalText implements encoding.TextMarshaler. The zero Term is marshaled
+// as an empty string.
+func (t Term) MarshalText() ([]byte, error) {
+	if t.IsZero() {
+		return []byte{}, nil
+	}
+	return []byte(t.String()), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler. An empty string is
+// unmarshaled as the zero Term.
+func (t *Term) UnmarshalText(data []byte) error {
+	if len(data) == 0 {
+		*t = Term{}
+		return nil
+	}
+	term, err := ParseTerm(string(data))
+	if err != nil {
+		return err
+	}
+	*t = term
+	return nil
+}
@@ -0,0 +1,98 @@
+/*
+Copyright 2021 The Go jAccount Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package jaccount
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/url"
+	"strconv"
+	"time"
+)
+
+// Timestamp is a time encoded by the jAccount API as the number of
+// milliseconds since the Unix epoch. The zero Timestamp is encoded as null.
+type Timestamp struct {
+	time.Time
+}
+
+// NewTimestamp returns the Timestamp of t.
+func NewTimestamp(t time.Time) Timestamp {
+	return Timestamp{t}
+}
+
+// Millis returns the number of milliseconds since the Unix epoch.
+func (t Timestamp) Millis() int64 {
+	if t.IsZero() {
+		return 0
+	}
+	return t.UnixNano() / int64(time.Millisecond)
+}
+
+// MarshalJSON implements json.Marshaler.
+func (t Timestamp) MarshalJSON() ([]byte, error) {
+	if t.IsZero() {
+		return []byte("null"), nil
+	}
+	return []byte(strconv.FormatInt(t.Millis(), 10)), nil
+}
+
+// UnmarshalJSON implements json.Unmarshaler. Besides milliseconds, numeric
+// strings and RFC 3339 strings are accepted, and null or 0 is the zero time.
+func (t *Timestamp) UnmarshalJSON(data []byte) error {
+	if bytes.Equal(data, []byte("null")) {
+		*t = Timestamp{}
+		return nil
+	}
+
+	var str string
+	if len(data) > 0 && data[0] == '"' {
+		if err := json.Unmarshal(data, &str); err != nil {
+			return err
+		}
+		if str == "" {
+			*t = Timestamp{}
+			return nil
+		}
+		if parsed, err := time.Parse(time.RFC3339, str); err == nil {
+			*t = Timestamp{parsed}
+			return nil
+		}
+	} else {
+		str = string(data)
+	}
+
+	millis, err := strconv.ParseInt(str, 10, 64)
+	if err != nil {
+		return err
+	}
+	if millis == 0 {
+		*t = Timestamp{}
+		return nil
+	}
+	*t = Timestamp{time.Unix(0, millis*int64(time.Millisecond))}
+	return nil
+}
+
+// EncodeValues implements query.Encoder, so that a Timestamp in the options
+// of a method is encoded as milliseconds.
+func (t Timestamp) EncodeValues(key string, v *url.Values) error {
+	if !t.IsZero() {
+		v.Set(key, strconv.FormatInt(t.Millis(), 10))
+	}
+	return nil
+}
@@ -105,6 +105,9 @@ type idToken struct {
 	Type string `json:"type"`
 }
 
+// VerifyToken parses an ID token and checks its issuer and expiry, but not its
+// signature. It is only safe for ID tokens received directly from the token
+// endpoint over TLS; use VerifySignedToken for any other token.
 func VerifyToken(rawToken string) (*IDToken, error) {
 	token, err := jwt.ParseSigned(rawToken)
 	if err != nil {
@@ -117,6 +120,11 @@ func VerifyToken(rawToken string) (*IDToken, error) {
 		return nil, err
 	}
 
+	return newIDToken(&idToken)
+}
+
+// newIDToken checks the claims of an ID token.
+func newIDToken(idToken *idToken) (*IDToken, error) {
 	t := &IDToken{
 		Issuer:   idToken.Issuer,
 		Audience: idToken.Audience,
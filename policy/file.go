@@ -0,0 +1,194 @@
+/*
+Copyright 2021 The Go jAccount Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package policy
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+
+	"github.com/dyweb/go-jaccount/jaccount"
+	"gopkg.in/yaml.v3"
+)
+
+// Spec is the declarative form of a policy, as found in policy files. All the
+// conditions given in a spec must hold.
+//
+// For example, the following YAML spec allows faculty of the School of
+// Software, and anyone holding post P0001:
+//
+//	any:
+//	  - type: [faculty, yxy]
+//	    org: ["03700"]
+//	  - post: [P0001]
+type Spec struct {
+	Type    []string `json:"type,omitempty" yaml:"type,omitempty"`
+	Org     []string `json:"org,omitempty" yaml:"org,omitempty"`
+	Post    []string `json:"post,omitempty" yaml:"post,omitempty"`
+	Account []string `json:"account,omitempty" yaml:"account,omitempty"`
+
+	All []*Spec `json:"all,omitempty" yaml:"all,omitempty"`
+	Any []*Spec `json:"any,omitempty" yaml:"any,omitempty"`
+	Not *Spec   `json:"not,omitempty" yaml:"not,omitempty"`
+}
+
+// Policy builds the policy described by the spec. An empty spec is an error,
+// since it would allow everyone.
+func (s *Spec) Policy() (Policy, error) {
+	if s == nil {
+		return nil, errors.New("empty policy spec")
+	}
+
+	var policies []Policy
+
+	if len(s.Type) > 0 {
+		types := make([]jaccount.Type, len(s.Type))
+		for i, t := range s.Type {
+			if !knownTypes[jaccount.Type(t)] {
+				return nil, fmt.Errorf("unknown user type %q", t)
+			}
+			types[i] = jaccount.Type(t)
+		}
+		policies = append(policies, RequireType(types...))
+	}
+	if len(s.Org) > 0 {
+		policies = append(policies, RequireOrg(s.Org...))
+	}
+	if len(s.Post) > 0 {
+		policies = append(policies, RequirePost(s.Post...))
+	}
+	if len(s.Account) > 0 {
+		policies = append(policies, RequireAccount(s.Account...))
+	}
+
+	for _, spec := range s.All {
+		p, err := spec.Policy()
+		if err != nil {
+			return nil, err
+		}
+		policies = append(policies, p)
+	}
+
+	if len(s.Any) > 0 {
+		anyOf := make([]Policy, len(s.Any))
+		for i, spec := range s.Any {
+			p, err := spec.Policy()
+			if err != nil {
+				return nil, err
+			}
+			anyOf[i] = p
+		}
+		policies = append(policies, Any(anyOf...))
+	}
+
+	if s.Not != nil {
+		p, err := s.Not.Policy()
+		if err != nil {
+			return nil, err
+		}
+		policies = append(policies, Not(p))
+	}
+
+	switch len(policies) {
+	case 0:
+		return nil, errors.New("empty policy spec")
+	case 1:
+		return policies[0], nil
+	default:
+		return All(policies...), nil
+	}
+}
+
+var knownTypes = map[jaccount.Type]bool{
+	jaccount.FACULTY:                 true,
+	jaccount.STUDENT:                 true,
+	jaccount.MEDICAL_SCHOOL_FACULTY:  true,
+	jaccount.AFFILIATED_UNIT_FACULTY: true,
+	jaccount.VIP:                     true,
+	jaccount.POSTPHD:                 true,
+	jaccount.EXTERNAL_TEACHER:        true,
+	jaccount.SUMMER:                  true,
+	jaccount.TEAM:                    true,
+	jaccount.ALUMNI:                  true,
+	jaccount.GREEN:                   true,
+	jaccount.OUTSIDE:                 true,
+}
+
+// File is a policy file, which names a set of policies.
+type File struct {
+	Policies map[string]*Spec `json:"policies" yaml:"policies"`
+}
+
+// Parse parses a policy file in JSON or YAML format, as given by format
+// ("json" or "yaml"), and builds its policies. Unknown fields are rejected,
+// since a misspelled condition would otherwise allow everyone.
+func Parse(data []byte, format string) (map[string]Policy, error) {
+	var f File
+	switch strings.ToLower(format) {
+	case "json":
+		dec := json.NewDecoder(bytes.NewReader(data))
+		dec.DisallowUnknownFields()
+		if err := dec.Decode(&f); err != nil {
+			return nil, err
+		}
+	case "yaml", "yml":
+		dec := yaml.NewDecoder(bytes.NewReader(data))
+		dec.KnownFields(true)
+		if err := dec.Decode(&f); err != nil {
+			return nil, err
+		}
+	default:
+		return nil, fmt.Errorf("unsupported policy file format %q", format)
+	}
+
+	if len(f.Policies) == 0 {
+		return nil, errors.New("no policies defined")
+	}
+
+	policies := make(map[string]Policy, len(f.Policies))
+	for name, spec := range f.Policies {
+		if spec == nil {
+			return nil, fmt.Errorf("policy %q: empty policy spec", name)
+		}
+		p, err := spec.Policy()
+		if err != nil {
+			return nil, fmt.Errorf("policy %q: %w", name, err)
+		}
+		policies[name] = p
+	}
+	return policies, nil
+}
+
+// LoadFile loads a policy file. Files ending in .yaml or .yml are parsed as
+// YAML, others as JSON.
+func LoadFile(path string) (map[string]Policy, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	format := "json"
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".yaml", ".yml":
+		format = "yaml"
+	}
+	return Parse(data, format)
+}
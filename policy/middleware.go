@@ -0,0 +1,70 @@
+/*
+Copyright 2021 The Go jAccount Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package policy
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/dyweb/go-jaccount/jaccount"
+)
+
+// Middleware enforces a policy on requests. It must run after the bearer or
+// session middleware that stores the identity of the user in the context.
+type Middleware struct {
+	Policy Policy
+
+	// Client, if set, returns a client for the user of the request, used to
+	// fetch the profile and positions when a policy needs them.
+	Client func(r *http.Request) *jaccount.Client
+
+	// Denied handles requests denied by the policy. Defaults to responding
+	// with 403 Forbidden.
+	Denied http.Handler
+}
+
+// Handler returns middleware that passes requests allowed by the policy to
+// next.
+func (m *Middleware) Handler(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		s := SubjectFromContext(r.Context())
+		if m.Client != nil {
+			s.Client = m.Client(r)
+		}
+
+		err := CheckSubject(r.Context(), m.Policy, s)
+		switch {
+		case err == nil:
+			next.ServeHTTP(w, r)
+		case errors.Is(err, ErrDenied):
+			if m.Denied != nil {
+				m.Denied.ServeHTTP(w, r)
+				return
+			}
+			http.Error(w, http.StatusText(http.StatusForbidden), http.StatusForbidden)
+		default:
+			http.Error(w, "failed to evaluate policy", http.StatusInternalServerError)
+		}
+	})
+}
+
+// Require returns middleware that enforces the policy with the default
+// options.
+func Require(p Policy) func(http.Handler) http.Handler {
+	m := &Middleware{Policy: p}
+	return m.Handler
+}
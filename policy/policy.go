@@ -0,0 +1,303 @@
+/*
+Copyright 2021 The Go jAccount Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+/*
+Package policy authorizes jAccount users by who they are: their user type,
+organization, enterprise positions or account.
+
+Policies are composable predicates over a Subject, built with RequireType,
+RequireOrg, RequirePost, RequireAccount, All, Any and Not, or loaded from a
+JSON or YAML policy file. They are enforced by Middleware, or checked directly
+with Allowed against the identity stored in a context by the bearer and session
+packages.
+*/
+package policy
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/dyweb/go-jaccount/jaccount"
+)
+
+// ErrDenied is returned by Check when the policy denies access.
+var ErrDenied = errors.New("access denied by policy")
+
+// ErrNoProfile and ErrNoPositions are returned by policies that need the
+// profile or the enterprise positions of a subject when they are unavailable.
+// Both wrap ErrDenied, and make Not deny access rather than allow it.
+var (
+	ErrNoProfile   = fmt.Errorf("%w: profile unavailable", ErrDenied)
+	ErrNoPositions = fmt.Errorf("%w: positions unavailable", ErrDenied)
+)
+
+// Subject is the user a policy is evaluated for.
+type Subject struct {
+	IDToken   *jaccount.IDToken
+	TokenInfo *jaccount.TokenInfo
+	Profile   *jaccount.Profile
+	Positions *jaccount.Positions
+
+	// Client, if set, is used to fetch Profile and Positions the first time a
+	// policy needs them.
+	Client *jaccount.Client
+
+	profileOnce   sync.Once
+	profileErr    error
+	positionsOnce sync.Once
+	positionsErr  error
+}
+
+// SubjectFromContext returns the subject whose identity is stored in ctx.
+func SubjectFromContext(ctx context.Context) *Subject {
+	s := &Subject{}
+	s.IDToken, _ = jaccount.IDTokenFromContext(ctx)
+	s.TokenInfo, _ = jaccount.TokenInfoFromContext(ctx)
+	s.Profile, _ = jaccount.ProfileFromContext(ctx)
+	return s
+}
+
+// GetProfile returns the profile of the subject, fetching it with Client if
+// needed. It returns nil if the profile is unavailable.
+func (s *Subject) GetProfile(ctx context.Context) (*jaccount.Profile, error) {
+	s.profileOnce.Do(func() {
+		if s.Profile == nil && s.Client != nil {
+			s.Profile, s.profileErr = s.Client.Profile.Get(ctx)
+		}
+	})
+	return s.Profile, s.profileErr
+}
+
+// getProfile is like GetProfile, but returns ErrNoProfile if the profile is
+// unavailable.
+func (s *Subject) getProfile(ctx context.Context) (*jaccount.Profile, error) {
+	profile, err := s.GetProfile(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if profile == nil {
+		return nil, ErrNoProfile
+	}
+	return profile, nil
+}
+
+// GetPositions returns the enterprise positions of the subject, fetching them
+// with Client if needed. It returns nil if the positions are unavailable.
+func (s *Subject) GetPositions(ctx context.Context) (*jaccount.Positions, error) {
+	s.positionsOnce.Do(func() {
+		if s.Positions == nil && s.Client != nil {
+			s.Positions, s.positionsErr = s.Client.Enterprise.GetUserPositions(ctx)
+		}
+	})
+	return s.Positions, s.positionsErr
+}
+
+// Policy decides whether a subject is allowed access.
+type Policy interface {
+	Allow(ctx context.Context, s *Subject) (bool, error)
+}
+
+// Func is an adapter to allow the use of ordinary functions as policies.
+type Func func(ctx context.Context, s *Subject) (bool, error)
+
+// Allow calls f(ctx, s).
+func (f Func) Allow(ctx context.Context, s *Subject) (bool, error) {
+	return f(ctx, s)
+}
+
+// Allowed reports whether the policy allows the user whose identity is stored
+// in ctx. Errors deny access.
+func Allowed(ctx context.Context, p Policy) bool {
+	return Check(ctx, p) == nil
+}
+
+// Check returns nil if the policy allows the user whose identity is stored in
+// ctx, ErrDenied if it does not, or the error that prevented the decision.
+func Check(ctx context.Context, p Policy) error {
+	return CheckSubject(ctx, p, SubjectFromContext(ctx))
+}
+
+// CheckSubject is like Check for an explicit subject.
+func CheckSubject(ctx context.Context, p Policy, s *Subject) error {
+	ok, err := p.Allow(ctx, s)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return ErrDenied
+	}
+	return nil
+}
+
+// RequireType allows users of any of the given types. Both the default type
+// of the user and the types of all their identities are considered, so unless
+// the type of the ID token matches, it returns ErrNoProfile when the profile
+// is unavailable.
+func RequireType(types ...jaccount.Type) Policy {
+	want := make(map[jaccount.Type]bool, len(types))
+	for _, t := range types {
+		want[t] = true
+	}
+
+	return Func(func(ctx context.Context, s *Subject) (bool, error) {
+		if s.IDToken != nil && want[s.IDToken.Type] {
+			return true, nil
+		}
+
+		profile, err := s.getProfile(ctx)
+		if err != nil {
+			return false, err
+		}
+		if want[jaccount.Type(profile.UserType)] {
+			return true, nil
+		}
+		for _, identity := range profile.Identities {
+			if identity != nil && want[jaccount.Type(identity.UserType)] {
+				return true, nil
+			}
+		}
+		return false, nil
+	})
+}
+
+// RequireOrg allows users who belong to any of the given organizations, as
+// given by Organize.ID in their profile or any of their identities. It
+// returns ErrNoProfile when the profile is unavailable.
+func RequireOrg(ids ...string) Policy {
+	want := stringSet(ids)
+
+	return Func(func(ctx context.Context, s *Subject) (bool, error) {
+		profile, err := s.getProfile(ctx)
+		if err != nil {
+			return false, err
+		}
+		if profile.Organize != nil && want[profile.Organize.ID] {
+			return true, nil
+		}
+		for _, identity := range profile.Identities {
+			if identity != nil && identity.Organize != nil && want[identity.Organize.ID] {
+				return true, nil
+			}
+		}
+		return false, nil
+	})
+}
+
+// RequirePost allows users who hold any of the given posts, as given by
+// Post.PostCode in their enterprise positions. It returns ErrNoPositions when
+// the positions are unavailable.
+func RequirePost(codes ...string) Policy {
+	want := stringSet(codes)
+
+	return Func(func(ctx context.Context, s *Subject) (bool, error) {
+		positions, err := s.GetPositions(ctx)
+		if err != nil {
+			return false, err
+		}
+		if positions == nil {
+			return false, ErrNoPositions
+		}
+		for _, position := range positions.Positions {
+			if want[position.Post.PostCode] {
+				return true, nil
+			}
+		}
+		return false, nil
+	})
+}
+
+// RequireAccount allows the given jAccount accounts. The account is taken from
+// the profile, the introspected token or the subject of the ID token, in that
+// order. It returns ErrNoProfile when none of them is available.
+func RequireAccount(accounts ...string) Policy {
+	want := stringSet(accounts)
+
+	return Func(func(ctx context.Context, s *Subject) (bool, error) {
+		if s.Profile != nil {
+			return want[s.Profile.Account], nil
+		}
+		if s.TokenInfo != nil && s.TokenInfo.Username != "" {
+			return want[s.TokenInfo.Username], nil
+		}
+		if s.IDToken != nil {
+			return want[s.IDToken.Subject], nil
+		}
+
+		profile, err := s.getProfile(ctx)
+		if err != nil {
+			return false, err
+		}
+		return want[profile.Account], nil
+	})
+}
+
+// All allows users allowed by all the policies.
+func All(policies ...Policy) Policy {
+	return Func(func(ctx context.Context, s *Subject) (bool, error) {
+		for _, p := range policies {
+			ok, err := p.Allow(ctx, s)
+			if err != nil || !ok {
+				return false, err
+			}
+		}
+		return true, nil
+	})
+}
+
+// Any allows users allowed by any of the policies. An error of one policy is
+// only returned if no other policy allows the user.
+func Any(policies ...Policy) Policy {
+	return Func(func(ctx context.Context, s *Subject) (bool, error) {
+		var firstErr error
+		for _, p := range policies {
+			ok, err := p.Allow(ctx, s)
+			if ok {
+				return true, nil
+			}
+			if err != nil && firstErr == nil {
+				firstErr = err
+			}
+		}
+		return false, firstErr
+	})
+}
+
+// Not allows users denied by the policy. Errors, including ErrNoProfile and
+// ErrNoPositions, deny access.
+func Not(p Policy) Policy {
+	return Func(func(ctx context.Context, s *Subject) (bool, error) {
+		ok, err := p.Allow(ctx, s)
+		if err != nil {
+			return false, err
+		}
+		return !ok, nil
+	})
+}
+
+// AllowAll allows every user.
+var AllowAll Policy = Func(func(ctx context.Context, s *Subject) (bool, error) {
+	return true, nil
+})
+
+func stringSet(values []string) map[string]bool {
+	set := make(map[string]bool, len(values))
+	for _, v := range values {
+		set[v] = true
+	}
+	return set
+}
@@ -0,0 +1,189 @@
+/*
+Copyright 2021 The Go jAccount Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package policy
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/dyweb/go-jaccount/jaccount"
+)
+
+var (
+	student = &Subject{
+		IDToken: &jaccount.IDToken{Type: jaccount.STUDENT},
+		Profile: &jaccount.Profile{
+			Account:  "student",
+			UserType: string(jaccount.STUDENT),
+			Organize: &jaccount.Organize{ID: "03700"},
+		},
+	}
+	faculty = &Subject{
+		IDToken: &jaccount.IDToken{Type: jaccount.MEDICAL_SCHOOL_FACULTY},
+		Profile: &jaccount.Profile{
+			Account:  "faculty",
+			UserType: string(jaccount.MEDICAL_SCHOOL_FACULTY),
+			Organize: &jaccount.Organize{ID: "01000"},
+			Identities: []*jaccount.Identity{
+				{UserType: string(jaccount.MEDICAL_SCHOOL_FACULTY), Organize: &jaccount.Organize{ID: "01000"}},
+				{UserType: string(jaccount.STUDENT), Organize: &jaccount.Organize{ID: "03700"}},
+			},
+		},
+		Positions: &jaccount.Positions{
+			Positions: []jaccount.Position{{Post: jaccount.Post{PostCode: "P0001"}}},
+		},
+	}
+)
+
+func TestPolicies(t *testing.T) {
+	tests := []struct {
+		name    string
+		policy  Policy
+		subject *Subject
+		want    bool
+	}{
+		{"type", RequireType(jaccount.STUDENT), student, true},
+		{"type of identity", RequireType(jaccount.STUDENT), faculty, true},
+		{"other type", RequireType(jaccount.FACULTY, jaccount.MEDICAL_SCHOOL_FACULTY), student, false},
+		{"org", RequireOrg("03700"), student, true},
+		{"other org", RequireOrg("01000"), student, false},
+		{"post", RequirePost("P0001"), faculty, true},
+		{"no positions", RequirePost("P0001"), student, false},
+		{"account", RequireAccount("faculty"), faculty, true},
+		{"all", All(RequireType(jaccount.STUDENT), RequireOrg("03700")), student, true},
+		{"all denied", All(RequireType(jaccount.STUDENT), RequirePost("P0001")), student, false},
+		{"any", Any(RequirePost("P0001"), RequireOrg("03700")), student, true},
+		{"not", Not(RequireType(jaccount.STUDENT)), student, false},
+		{"no identity", RequireType(jaccount.STUDENT), &Subject{}, false},
+		{"not without profile", Not(RequireType(jaccount.STUDENT)), &Subject{}, false},
+		{"not org without profile", Not(RequireOrg("03700")), &Subject{IDToken: &jaccount.IDToken{Type: jaccount.FACULTY}}, false},
+		{"not post without positions", Not(RequirePost("P0001")), student, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := CheckSubject(context.Background(), tt.policy, tt.subject)
+			if err != nil && !errors.Is(err, ErrDenied) {
+				t.Errorf("CheckSubject() error = %v", err)
+				return
+			}
+			if got := err == nil; got != tt.want {
+				t.Errorf("CheckSubject() allowed = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAllowed(t *testing.T) {
+	ctx := jaccount.ContextWithIDToken(context.Background(), &jaccount.IDToken{Type: jaccount.FACULTY})
+
+	if !Allowed(ctx, RequireType(jaccount.FACULTY)) {
+		t.Errorf("Allowed() = false, want true")
+	}
+	if Allowed(ctx, RequireType(jaccount.STUDENT)) {
+		t.Errorf("Allowed() = true, want false")
+	}
+}
+
+func TestMiddleware(t *testing.T) {
+	handler := Require(RequireType(jaccount.STUDENT))(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+
+	tests := []struct {
+		name       string
+		idToken    *jaccount.IDToken
+		wantStatus int
+	}{
+		{"allowed", &jaccount.IDToken{Type: jaccount.STUDENT}, http.StatusOK},
+		{"denied", &jaccount.IDToken{Type: jaccount.ALUMNI}, http.StatusForbidden},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			req = req.WithContext(jaccount.ContextWithIDToken(req.Context(), tt.idToken))
+
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, req)
+			if rec.Code != tt.wantStatus {
+				t.Errorf("Middleware.Handler() status = %d, want %d", rec.Code, tt.wantStatus)
+			}
+		})
+	}
+}
+
+func TestParse(t *testing.T) {
+	const yamlFile = `
+policies:
+  software:
+    any:
+      - type: [faculty, yxy]
+        org: ["03700"]
+      - post: [P0001]
+  students:
+    type: [student]
+`
+	const jsonFile = `{"policies": {"software": {"any": [{"type": ["faculty", "yxy"], "org": ["03700"]}, {"post": ["P0001"]}]}, "students": {"type": ["student"]}}}`
+
+	tests := []struct {
+		name    string
+		data    string
+		format  string
+		wantErr bool
+	}{
+		{"yaml", yamlFile, "yaml", false},
+		{"json", jsonFile, "json", false},
+		{"unknown field", `{"policies": {"students": {"types": ["student"]}}}`, "json", true},
+		{"unknown type", `{"policies": {"students": {"type": ["pupil"]}}}`, "json", true},
+		{"unknown format", jsonFile, "toml", true},
+		{"empty spec", "policies:\n  admin:\n", "yaml", true},
+		{"empty object", `{"policies": {"admin": {}}}`, "json", true},
+		{"nested empty spec", `{"policies": {"admin": {"any": [{"post": ["P0001"]}, {}]}}}`, "json", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			policies, err := Parse([]byte(tt.data), tt.format)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Parse() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if tt.wantErr {
+				return
+			}
+
+			checks := []struct {
+				policy  string
+				subject *Subject
+				want    bool
+			}{
+				{"software", faculty, true},
+				{"software", student, false},
+				{"students", student, true},
+				{"students", &Subject{IDToken: &jaccount.IDToken{Type: jaccount.FACULTY}}, false},
+			}
+			for _, c := range checks {
+				err := CheckSubject(context.Background(), policies[c.policy], c.subject)
+				if got := err == nil; got != c.want || err != nil && !errors.Is(err, ErrDenied) {
+					t.Errorf("policy %q CheckSubject() = %v, want allowed %v", c.policy, err, c.want)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,161 @@
+/*
+Copyright 2021 The Go jAccount Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package proxy
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/dyweb/go-jaccount/jaccount"
+)
+
+// Identity headers forwarded to the upstream.
+const (
+	HeaderAccount   = "X-Jaccount-Account"
+	HeaderName      = "X-Jaccount-Name"
+	HeaderCode      = "X-Jaccount-Code"
+	HeaderType      = "X-Jaccount-Type"
+	HeaderOrg       = "X-Jaccount-Org"
+	HeaderTimestamp = "X-Jaccount-Timestamp"
+	HeaderSignature = "X-Jaccount-Signature"
+
+	headerPrefix = "X-Jaccount-"
+)
+
+// signedHeaders are the headers covered by the signature, in order.
+var signedHeaders = []string{HeaderAccount, HeaderName, HeaderCode, HeaderType, HeaderOrg, HeaderTimestamp}
+
+// Identity is the identity of the user forwarded to the upstream.
+type Identity struct {
+	Account string
+	Name    string
+	Code    string
+	Type    jaccount.Type
+	Org     string
+}
+
+// IdentityFrom returns the identity of the user from the ID token and, if
+// available, the profile.
+func IdentityFrom(idToken *jaccount.IDToken, profile *jaccount.Profile) *Identity {
+	id := &Identity{
+		Account: idToken.Subject,
+		Name:    idToken.Name,
+		Code:    idToken.Code,
+		Type:    idToken.Type,
+	}
+	if profile != nil {
+		id.Account = profile.Account
+		if profile.Organize != nil {
+			id.Org = profile.Organize.ID
+		}
+	}
+	return id
+}
+
+// SetHeaders replaces the identity headers of r, removing any X-Jaccount-*
+// header sent by the client. If key is not empty, the headers are signed
+// together with the method and URI of the request, so the upstream can tell
+// they were set by the proxy.
+func SetHeaders(r *http.Request, id *Identity, key []byte) {
+	for name := range r.Header {
+		if strings.HasPrefix(http.CanonicalHeaderKey(name), headerPrefix) {
+			r.Header.Del(name)
+		}
+	}
+	WriteHeaders(r.Header, r.Method, r.URL.RequestURI(), id, key)
+}
+
+// WriteHeaders writes the identity headers for a request with the given
+// method and URI into h, signing them if key is not empty. It is used where
+// the headers are not set on the request itself, such as in the response of
+// a forward authentication endpoint.
+func WriteHeaders(h http.Header, method string, uri string, id *Identity, key []byte) {
+	h.Set(HeaderAccount, id.Account)
+	h.Set(HeaderName, encodeHeader(id.Name))
+	h.Set(HeaderCode, id.Code)
+	h.Set(HeaderType, string(id.Type))
+	h.Set(HeaderOrg, id.Org)
+
+	if len(key) > 0 {
+		h.Set(HeaderTimestamp, strconv.FormatInt(time.Now().Unix(), 10))
+		h.Set(HeaderSignature, sign(h, method, uri, key))
+	}
+}
+
+// VerifyHeaders verifies the signature of the identity headers of a request
+// received from the proxy, and returns the identity. Signatures older than
+// maxAge are rejected.
+func VerifyHeaders(r *http.Request, key []byte, maxAge time.Duration) (*Identity, error) {
+	signature, err := base64.RawURLEncoding.DecodeString(r.Header.Get(HeaderSignature))
+	if err != nil || len(signature) == 0 {
+		return nil, errors.New("missing identity signature")
+	}
+
+	expected, _ := base64.RawURLEncoding.DecodeString(sign(r.Header, r.Method, r.URL.RequestURI(), key))
+	if !hmac.Equal(signature, expected) {
+		return nil, errors.New("invalid identity signature")
+	}
+
+	timestamp, err := strconv.ParseInt(r.Header.Get(HeaderTimestamp), 10, 64)
+	if err != nil {
+		return nil, errors.New("invalid identity timestamp")
+	}
+	if age := time.Since(time.Unix(timestamp, 0)); age > maxAge || age < -maxAge {
+		return nil, errors.New("identity signature expired")
+	}
+
+	return &Identity{
+		Account: r.Header.Get(HeaderAccount),
+		Name:    decodeHeader(r.Header.Get(HeaderName)),
+		Code:    r.Header.Get(HeaderCode),
+		Type:    jaccount.Type(r.Header.Get(HeaderType)),
+		Org:     r.Header.Get(HeaderOrg),
+	}, nil
+}
+
+func sign(h http.Header, method string, uri string, key []byte) string {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(method))
+	mac.Write([]byte{'\n'})
+	mac.Write([]byte(uri))
+	for _, name := range signedHeaders {
+		mac.Write([]byte{'\n'})
+		mac.Write([]byte(h.Get(name)))
+	}
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
+
+// encodeHeader percent-encodes values that may contain non-ASCII characters,
+// such as Chinese names, which are not allowed in header values.
+func encodeHeader(value string) string {
+	return url.PathEscape(value)
+}
+
+func decodeHeader(value string) string {
+	decoded, err := url.PathUnescape(value)
+	if err != nil {
+		return value
+	}
+	return decoded
+}
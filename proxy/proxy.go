@@ -0,0 +1,180 @@
+/*
+Copyright 2021 The Go jAccount Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+/*
+Package proxy implements an authenticating reverse proxy that puts jAccount
+login in front of an upstream HTTP service.
+
+Users are logged in with the session package. Requests of users allowed by the
+policy are forwarded to the upstream with their identity in the X-Jaccount-*
+headers, optionally signed so that the upstream can reject spoofed headers.
+
+The proxy may run behind a TLS terminator, which must set X-Forwarded-Proto
+for the session cookies to be marked secure.
+*/
+package proxy
+
+import (
+	"net/http"
+	"net/http/httputil"
+	"net/url"
+	"strings"
+	"sync"
+
+	"github.com/dyweb/go-jaccount/jaccount"
+	"github.com/dyweb/go-jaccount/policy"
+	"github.com/dyweb/go-jaccount/session"
+)
+
+// Default paths served by the proxy itself.
+const (
+	DefaultPrefix     = "/oauth2"
+	DefaultHealthPath = "/healthz"
+)
+
+// Proxy is an authenticating reverse proxy.
+type Proxy struct {
+	// Upstream is the URL of the protected service.
+	Upstream *url.URL
+
+	// Session manages the login sessions. Its LoginURL and
+	// Config.RedirectURL must point to Prefix + "/login" and Prefix +
+	// "/callback".
+	Session *session.Manager
+
+	// Policy decides which users may access the upstream. Defaults to
+	// everyone with a jAccount.
+	Policy policy.Policy
+
+	// SigningKey, if set, is used to sign the identity headers.
+	SigningKey []byte
+
+	// Prefix is the path prefix of the login, callback and logout handlers.
+	// Defaults to "/oauth2".
+	Prefix string
+
+	// HealthPath is the path of the health check. Defaults to "/healthz".
+	HealthPath string
+
+	once    sync.Once
+	handler http.Handler
+}
+
+// Handler returns the HTTP handler of the proxy.
+func (p *Proxy) Handler() http.Handler {
+	prefix := p.Prefix
+	if prefix == "" {
+		prefix = DefaultPrefix
+	}
+	healthPath := p.HealthPath
+	if healthPath == "" {
+		healthPath = DefaultHealthPath
+	}
+	allow := p.Policy
+	if allow == nil {
+		allow = policy.AllowAll
+	}
+
+	reverseProxy := httputil.NewSingleHostReverseProxy(p.Upstream)
+	director := reverseProxy.Director
+	reverseProxy.Director = func(r *http.Request) {
+		host := r.Host
+		director(r)
+
+		// The upstream sees its own host, and not the session cookie.
+		r.Host = p.Upstream.Host
+		r.Header.Set("X-Forwarded-Host", host)
+		if session.IsSecure(r) {
+			r.Header.Set("X-Forwarded-Proto", "https")
+		} else {
+			r.Header.Set("X-Forwarded-Proto", "http")
+		}
+		removeCookie(r, p.cookieName())
+
+		// The headers are signed over the URI the upstream receives, which
+		// includes the path of Upstream.
+		s, _ := session.FromContext(r.Context())
+		SetHeaders(r, IdentityFrom(s.IDToken, s.Profile), p.SigningKey)
+	}
+
+	mux := http.NewServeMux()
+	mux.Handle(prefix+"/login", p.Session.LoginHandler())
+	mux.Handle(prefix+"/callback", p.Session.CallbackHandler())
+	mux.Handle(prefix+"/logout", p.Session.LogoutHandler())
+	mux.HandleFunc(healthPath, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+		w.Write([]byte("OK"))
+	})
+	mux.Handle("/", p.Session.RequireLogin(policy.Require(allow)(reverseProxy)))
+
+	return mux
+}
+
+// ServeHTTP implements http.Handler.
+func (p *Proxy) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	p.once.Do(func() {
+		p.handler = p.Handler()
+	})
+	p.handler.ServeHTTP(w, r)
+}
+
+func (p *Proxy) cookieName() string {
+	switch store := p.Session.Store.(type) {
+	case *session.CookieStore:
+		return store.Cookie.CookieName()
+	case *session.ServerStore:
+		return store.Cookie.CookieName()
+	default:
+		return session.DefaultCookieName
+	}
+}
+
+// removeCookie removes the named cookie from the Cookie header of r.
+func removeCookie(r *http.Request, name string) {
+	cookies := r.Cookies()
+	r.Header.Del("Cookie")
+
+	var kept []string
+	for _, c := range cookies {
+		if c.Name != name {
+			kept = append(kept, c.String())
+		}
+	}
+	if len(kept) > 0 {
+		r.Header.Set("Cookie", strings.Join(kept, "; "))
+	}
+}
+
+// AllowList builds the policy of the proxy from allow-lists. A user is allowed
+// if they match any of the non-empty lists; if all lists are empty, every
+// user is allowed.
+func AllowList(types []jaccount.Type, accounts []string, orgs []string) policy.Policy {
+	var policies []policy.Policy
+	if len(types) > 0 {
+		policies = append(policies, policy.RequireType(types...))
+	}
+	if len(accounts) > 0 {
+		policies = append(policies, policy.RequireAccount(accounts...))
+	}
+	if len(orgs) > 0 {
+		policies = append(policies, policy.RequireOrg(orgs...))
+	}
+
+	if len(policies) == 0 {
+		return policy.AllowAll
+	}
+	return policy.Any(policies...)
+}
@@ -0,0 +1,174 @@
+/*
+Copyright 2021 The Go jAccount Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package proxy
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/dyweb/go-jaccount/jaccount"
+	"github.com/dyweb/go-jaccount/session"
+	"golang.org/x/oauth2"
+)
+
+func TestProxy(t *testing.T) {
+	key := []byte("0123456789abcdef0123456789abcdef")
+
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if _, err := r.Cookie(session.DefaultCookieName); err == nil {
+			t.Errorf("session cookie forwarded to the upstream")
+		}
+		if !strings.HasPrefix(r.URL.Path, "/app/") {
+			t.Errorf("upstream path = %q, want prefix /app/", r.URL.Path)
+		}
+
+		id, err := VerifyHeaders(r, key, time.Minute)
+		if err != nil {
+			t.Errorf("VerifyHeaders() error = %v", err)
+			return
+		}
+		w.Write([]byte(id.Account + " " + id.Name + " " + string(id.Type) + " " + id.Org))
+	}))
+	defer upstream.Close()
+
+	upstreamURL, _ := url.Parse(upstream.URL + "/app")
+
+	backend := &session.MemoryBackend{}
+	now := time.Now()
+	sessions := map[string]*session.Session{
+		"student": {
+			ID:        "student",
+			IDToken:   &jaccount.IDToken{Subject: "student", Name: "张三", Type: jaccount.STUDENT, Expiry: now.Add(time.Hour)},
+			Profile:   &jaccount.Profile{Account: "student", Organize: &jaccount.Organize{ID: "03700"}},
+			CreatedAt: now,
+			LastSeen:  now,
+		},
+		"alumni": {
+			ID:        "alumni",
+			IDToken:   &jaccount.IDToken{Subject: "alumni", Type: jaccount.ALUMNI, Expiry: now.Add(time.Hour)},
+			CreatedAt: now,
+			LastSeen:  now,
+		},
+	}
+	for _, s := range sessions {
+		backend.Set(context.Background(), s, now.Add(time.Hour))
+	}
+
+	p := &Proxy{
+		Upstream: upstreamURL,
+		Session: &session.Manager{
+			Config:   &oauth2.Config{ClientID: "client", Endpoint: jaccount.Endpoint},
+			Store:    &session.ServerStore{Backend: backend},
+			LoginURL: "/oauth2/login",
+		},
+		Policy:     AllowList([]jaccount.Type{jaccount.STUDENT}, nil, nil),
+		SigningKey: key,
+	}
+
+	tests := []struct {
+		name         string
+		method       string
+		path         string
+		session      string
+		wantStatus   int
+		wantBody     string
+		wantLocation string
+	}{
+		{"allowed", http.MethodGet, "/page", "student", http.StatusOK, "student 张三 student 03700", ""},
+		{"denied", http.MethodGet, "/page", "alumni", http.StatusForbidden, "Forbidden\n", ""},
+		{"unauthenticated", http.MethodGet, "/page?q=1", "", http.StatusFound, "", "/oauth2/login?return=%2Fpage%3Fq%3D1"},
+		{"health", http.MethodGet, "/healthz", "", http.StatusOK, "OK", ""},
+		{"logout with GET", http.MethodGet, "/oauth2/logout", "student", http.StatusMethodNotAllowed, "", ""},
+		{"logout", http.MethodPost, "/oauth2/logout", "alumni", http.StatusFound, "", "/"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(tt.method, tt.path, nil)
+			req.Header.Set(HeaderAccount, "spoofed")
+			if tt.session != "" {
+				req.AddCookie(&http.Cookie{Name: session.DefaultCookieName, Value: tt.session})
+			}
+
+			rec := httptest.NewRecorder()
+			p.ServeHTTP(rec, req)
+
+			if rec.Code != tt.wantStatus {
+				t.Errorf("Proxy status = %d, want %d", rec.Code, tt.wantStatus)
+			}
+			if tt.wantBody != "" && rec.Body.String() != tt.wantBody {
+				t.Errorf("Proxy body = %q, want %q", rec.Body, tt.wantBody)
+			}
+			if got := rec.Header().Get("Location"); got != tt.wantLocation {
+				t.Errorf("Proxy Location = %q, want %q", got, tt.wantLocation)
+			}
+		})
+	}
+}
+
+func TestVerifyHeaders(t *testing.T) {
+	key := []byte("key")
+	id := &Identity{Account: "test", Type: jaccount.STUDENT}
+
+	req := httptest.NewRequest(http.MethodGet, "/page", nil)
+	SetHeaders(req, id, key)
+
+	if _, err := VerifyHeaders(req, key, time.Minute); err != nil {
+		t.Errorf("VerifyHeaders() error = %v", err)
+	}
+	if _, err := VerifyHeaders(req, []byte("other"), time.Minute); err == nil {
+		t.Errorf("VerifyHeaders() with another key error = nil")
+	}
+
+	req.Header.Set(HeaderAccount, "spoofed")
+	if _, err := VerifyHeaders(req, key, time.Minute); err == nil {
+		t.Errorf("VerifyHeaders() of modified headers error = nil")
+	}
+}
+
+func TestProxy_SecureCookies(t *testing.T) {
+	p := &Proxy{
+		Upstream: &url.URL{Scheme: "http", Host: "upstream"},
+		Session: &session.Manager{
+			Config: &oauth2.Config{ClientID: "client", Endpoint: jaccount.Endpoint},
+			Store:  &session.ServerStore{Backend: &session.MemoryBackend{}},
+		},
+	}
+
+	for proto, want := range map[string]bool{"https": true, "http": false} {
+		req := httptest.NewRequest(http.MethodGet, "/oauth2/login", nil)
+		req.Header.Set("X-Forwarded-Proto", proto)
+
+		rec := httptest.NewRecorder()
+		p.ServeHTTP(rec, req)
+
+		cookies := rec.Result().Cookies()
+		if len(cookies) == 0 {
+			t.Fatalf("login sets no cookies")
+		}
+		for _, c := range cookies {
+			if c.Secure != want {
+				t.Errorf("cookie %q Secure = %v behind %s, want %v", c.Name, c.Secure, proto, want)
+			}
+		}
+	}
+}
@@ -0,0 +1,30 @@
+/*
+Copyright 2021 The Go jAccount Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+/*
+Package session keeps the jAccount user logged in to a web application.
+
+A Manager implements the login, callback and logout handlers of the OpenID
+Connect authorization code flow, and stores the ID token and OAuth 2.0 token of
+the user in a Session. Sessions are kept either in an encrypted cookie with
+CookieStore or on the server with ServerStore. They expire after a period of
+inactivity, after an absolute lifetime, and never outlive the ID token.
+
+Middleware loads the session into the request context, where CurrentUser and
+FromContext read it, and RequireLogin sends unauthenticated users to the login
+handler, back to the page they asked for after login.
+*/
+package session
@@ -0,0 +1,403 @@
+/*
+Copyright 2021 The Go jAccount Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package session
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/dyweb/go-jaccount/jaccount"
+	"golang.org/x/oauth2"
+)
+
+const (
+	defaultIdleTimeout = 30 * time.Minute
+	defaultMaxAge      = 24 * time.Hour
+	defaultLoginURL    = "/login"
+
+	loginCookieName   = "jaccount_login"
+	loginCookieMaxAge = 10 * time.Minute
+
+	// lastSeenInterval limits how often the sliding expiry is written back
+	// to the store.
+	lastSeenInterval = time.Minute
+
+	// ReturnParam is the query parameter of the login handler that holds the
+	// URL to return to after login.
+	ReturnParam = "return"
+)
+
+// Manager manages the login sessions of jAccount users.
+type Manager struct {
+	// Config is the OAuth 2.0 configuration of the application. Its Scopes
+	// should include jaccount.ScopeOpenID, and its RedirectURL must point to
+	// the CallbackHandler.
+	Config *oauth2.Config
+
+	Store Store
+
+	// IdleTimeout is the sliding expiry of sessions. Defaults to 30 minutes;
+	// a negative value disables it.
+	IdleTimeout time.Duration
+
+	// MaxAge is the absolute expiry of sessions since login. Defaults to 24
+	// hours; a negative value disables it. Sessions never outlive the ID
+	// token regardless.
+	MaxAge time.Duration
+
+	// LoginURL is where RequireLogin sends unauthenticated users. Defaults
+	// to "/login".
+	LoginURL string
+
+	// DefaultReturnURL is where users are sent after login or logout when no
+	// safe return URL is given. Defaults to "/".
+	DefaultReturnURL string
+
+	// AllowedHosts lists the hosts that absolute return URLs may point to,
+	// for when the login handlers serve several applications. An entry
+	// starting with a dot matches all subdomains. By default only local
+	// paths are allowed.
+	AllowedHosts []string
+
+	// PostLogoutRedirectURL, if set, makes LogoutHandler also log the user
+	// out of jAccount, which then redirects to this URL.
+	PostLogoutRedirectURL string
+
+	// FetchProfile makes the callback fetch the profile of the user into
+	// the session, which needs the essential scope. The profile makes
+	// sessions too large for a CookieStore for some users, so use a
+	// ServerStore with it.
+	FetchProfile bool
+
+	// Verify verifies the raw ID token. Defaults to jaccount.VerifyToken.
+	Verify func(rawIDToken string) (*jaccount.IDToken, error)
+}
+
+// Middleware loads the session of the request into its context. Requests
+// without a valid session are passed on unchanged; use RequireLogin to reject
+// them.
+func (m *Manager) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		s, err := m.Load(w, r)
+		if err != nil {
+			http.Error(w, "failed to load session", http.StatusInternalServerError)
+			return
+		}
+		if s != nil {
+			r = r.WithContext(NewContext(r.Context(), s))
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// RequireLogin is like Middleware, but sends users without a session to the
+// login page, returning to the requested page afterwards. Requests that
+// cannot be redirected, such as POST requests, are rejected with 401.
+func (m *Manager) RequireLogin(next http.Handler) http.Handler {
+	return m.Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if _, ok := FromContext(r.Context()); ok {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		if r.Method != http.MethodGet && r.Method != http.MethodHead {
+			http.Error(w, http.StatusText(http.StatusUnauthorized), http.StatusUnauthorized)
+			return
+		}
+		http.Redirect(w, r, m.LoginRedirectURL(r.URL.RequestURI()), http.StatusFound)
+	}))
+}
+
+// LoginRedirectURL returns the URL of the login handler that returns to
+// returnURL after login.
+func (m *Manager) LoginRedirectURL(returnURL string) string {
+	loginURL := m.LoginURL
+	if loginURL == "" {
+		loginURL = defaultLoginURL
+	}
+
+	u, err := url.Parse(loginURL)
+	if err != nil {
+		return loginURL
+	}
+	q := u.Query()
+	q.Set(ReturnParam, returnURL)
+	u.RawQuery = q.Encode()
+	return u.String()
+}
+
+// Load returns the valid session of the request, or nil if there is none.
+// Expired sessions are deleted, and the sliding expiry of valid ones is
+// extended.
+func (m *Manager) Load(w http.ResponseWriter, r *http.Request) (*Session, error) {
+	s, err := m.Store.Load(r)
+	if err != nil || s == nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	if expires := s.ExpiresAt(m.idleTimeout(), m.maxAge()); !expires.IsZero() && !now.Before(expires) {
+		return nil, m.Store.Delete(w, r)
+	}
+
+	if now.Sub(s.LastSeen) >= lastSeenInterval {
+		s.LastSeen = now
+		if err := m.Save(w, r, s); err != nil {
+			return nil, err
+		}
+	}
+
+	return s, nil
+}
+
+// Save stores the session, for example after its token has been replaced.
+func (m *Manager) Save(w http.ResponseWriter, r *http.Request, s *Session) error {
+	return m.Store.Save(w, r, s, s.ExpiresAt(m.idleTimeout(), m.maxAge()))
+}
+
+// Client returns a jAccount API client that authenticates with the token of
+// the session, refreshing it when needed. A refreshed token replaces the token
+// of s, which the caller must then Save before writing the response, or the
+// next request refreshes it again with a refresh token that may have been
+// rotated.
+func (m *Manager) Client(ctx context.Context, s *Session) *jaccount.Client {
+	token := s.OAuth2Token()
+	src := &sessionTokenSource{session: s, base: m.Config.TokenSource(ctx, token)}
+	return jaccount.NewClient(oauth2.NewClient(ctx, oauth2.ReuseTokenSource(token, src)))
+}
+
+// sessionTokenSource replaces the token of the session when it is refreshed.
+// Calls are serialized by the oauth2.ReuseTokenSource wrapping it.
+type sessionTokenSource struct {
+	session *Session
+	base    oauth2.TokenSource
+}
+
+func (s *sessionTokenSource) Token() (*oauth2.Token, error) {
+	t, err := s.base.Token()
+	if err != nil {
+		return nil, err
+	}
+	if s.session.Token == nil || t.AccessToken != s.session.Token.AccessToken {
+		// Refresh responses may leave out the scope, which is unchanged.
+		scope := s.session.Scope
+		s.session.SetOAuth2Token(t)
+		if s.session.Scope == "" {
+			s.session.Scope = scope
+		}
+	}
+	return s.session.OAuth2Token(), nil
+}
+
+// LoginHandler starts the login by redirecting to jAccount. The ReturnParam
+// query parameter is where the user is sent after login.
+func (m *Manager) LoginHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		state := newID()
+		nonce := newID()
+
+		values := url.Values{}
+		values.Set("state", state)
+		values.Set("nonce", nonce)
+		values.Set("return", m.safeReturnURL(r.URL.Query().Get(ReturnParam)))
+
+		http.SetCookie(w, &http.Cookie{
+			Name:     loginCookieName,
+			Value:    base64.RawURLEncoding.EncodeToString([]byte(values.Encode())),
+			Path:     "/",
+			MaxAge:   int(loginCookieMaxAge.Seconds()),
+			Secure:   IsSecure(r),
+			HttpOnly: true,
+			SameSite: http.SameSiteLaxMode,
+		})
+
+		url := m.Config.AuthCodeURL(state, oauth2.SetAuthURLParam("nonce", nonce))
+		http.Redirect(w, r, url, http.StatusFound)
+	})
+}
+
+// CallbackHandler completes the login, creates a new session and sends the
+// user back to the page given to the LoginHandler.
+func (m *Manager) CallbackHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		s, returnURL, err := m.callback(w, r)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		// A new session is created on every login to prevent session
+		// fixation.
+		if err := m.Store.Delete(w, r); err != nil {
+			http.Error(w, "failed to delete session", http.StatusInternalServerError)
+			return
+		}
+		if err := m.Save(w, r, s); err != nil {
+			http.Error(w, "failed to save session", http.StatusInternalServerError)
+			return
+		}
+
+		http.Redirect(w, r, returnURL, http.StatusFound)
+	})
+}
+
+func (m *Manager) callback(w http.ResponseWriter, r *http.Request) (*Session, string, error) {
+	cookie, err := r.Cookie(loginCookieName)
+	if err != nil {
+		return nil, "", errors.New("login state not found")
+	}
+	http.SetCookie(w, &http.Cookie{Name: loginCookieName, Path: "/", MaxAge: -1, Secure: IsSecure(r), HttpOnly: true})
+
+	raw, err := base64.RawURLEncoding.DecodeString(cookie.Value)
+	if err != nil {
+		return nil, "", errors.New("malformed login state")
+	}
+	values, err := url.ParseQuery(string(raw))
+	if err != nil {
+		return nil, "", errors.New("malformed login state")
+	}
+
+	query := r.URL.Query()
+	if query.Get("state") == "" || query.Get("state") != values.Get("state") {
+		return nil, "", errors.New("state mismatch")
+	}
+	if e := query.Get("error"); e != "" {
+		return nil, "", fmt.Errorf("login failed: %s", e)
+	}
+
+	token, err := m.Config.Exchange(r.Context(), query.Get("code"))
+	if err != nil {
+		return nil, "", errors.New("failed to exchange token")
+	}
+
+	rawIDToken, ok := token.Extra("id_token").(string)
+	if !ok {
+		return nil, "", errors.New("failed to get ID token in OAuth2 token")
+	}
+
+	verify := m.Verify
+	if verify == nil {
+		verify = jaccount.VerifyToken
+	}
+	idToken, err := verify(rawIDToken)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to verify ID token: %s", err)
+	}
+	if idToken.Nonce != values.Get("nonce") {
+		return nil, "", errors.New("nonce mismatch")
+	}
+	if idToken.Audience != m.Config.ClientID {
+		return nil, "", errors.New("ID token issued to a different client")
+	}
+
+	now := time.Now()
+	s := &Session{
+		IDToken:   idToken,
+		CreatedAt: now,
+		LastSeen:  now,
+	}
+	s.SetOAuth2Token(token)
+
+	if m.FetchProfile {
+		profile, err := m.Client(r.Context(), s).Profile.Get(r.Context())
+		if err != nil {
+			return nil, "", fmt.Errorf("failed to fetch profile: %s", err)
+		}
+		s.Profile = profile
+	}
+
+	return s, m.safeReturnURL(values.Get("return")), nil
+}
+
+// LogoutHandler deletes the session and redirects to the ReturnParam query
+// parameter, or to jAccount's logout page if PostLogoutRedirectURL is set. It
+// only accepts POST, so that cross-site links and images cannot log users
+// out.
+func (m *Manager) LogoutHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			w.Header().Set("Allow", http.MethodPost)
+			http.Error(w, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+			return
+		}
+
+		if err := m.Store.Delete(w, r); err != nil {
+			http.Error(w, "failed to delete session", http.StatusInternalServerError)
+			return
+		}
+
+		if m.PostLogoutRedirectURL != "" {
+			values := url.Values{}
+			values.Set("client_id", m.Config.ClientID)
+			values.Set("post_logout_redirect_uri", m.PostLogoutRedirectURL)
+			http.Redirect(w, r, jaccount.LogoutURL+"?"+values.Encode(), http.StatusFound)
+			return
+		}
+
+		http.Redirect(w, r, m.safeReturnURL(r.URL.Query().Get(ReturnParam)), http.StatusFound)
+	})
+}
+
+// safeReturnURL returns raw if it is a local path or an absolute URL of an
+// allowed host, or the default return URL otherwise.
+func (m *Manager) safeReturnURL(raw string) string {
+	if u, err := url.Parse(raw); err == nil && (u.Scheme == "https" || u.Scheme == "http") && u.User == nil && m.allowedHost(u.Hostname()) {
+		return u.String()
+	}
+	return jaccount.SafeReturnURL(raw, m.defaultReturnURL())
+}
+
+func (m *Manager) allowedHost(host string) bool {
+	if host == "" {
+		return false
+	}
+	host = strings.ToLower(host)
+	for _, allowed := range m.AllowedHosts {
+		allowed = strings.ToLower(allowed)
+		if host == allowed || (strings.HasPrefix(allowed, ".") && strings.HasSuffix(host, allowed)) {
+			return true
+		}
+	}
+	return false
+}
+
+func (m *Manager) idleTimeout() time.Duration {
+	if m.IdleTimeout == 0 {
+		return defaultIdleTimeout
+	}
+	return m.IdleTimeout
+}
+
+func (m *Manager) maxAge() time.Duration {
+	if m.MaxAge == 0 {
+		return defaultMaxAge
+	}
+	return m.MaxAge
+}
+
+func (m *Manager) defaultReturnURL() string {
+	if m.DefaultReturnURL == "" {
+		return "/"
+	}
+	return m.DefaultReturnURL
+}
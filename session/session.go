@@ -0,0 +1,113 @@
+/*
+Copyright 2021 The Go jAccount Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package session
+
+import (
+	"context"
+	"time"
+
+	"github.com/dyweb/go-jaccount/jaccount"
+	"golang.org/x/oauth2"
+)
+
+// Session is the login session of a jAccount user.
+type Session struct {
+	// ID identifies the session in a server-side store. It is empty for
+	// sessions stored in cookies.
+	ID string `json:"id,omitempty"`
+
+	IDToken *jaccount.IDToken `json:"idToken"`
+	Token   *oauth2.Token     `json:"token,omitempty"`
+
+	// Profile is the profile of the user, fetched at login if
+	// Manager.FetchProfile is set.
+	Profile *jaccount.Profile `json:"profile,omitempty"`
+
+	// Scope is the space-separated list of scopes granted to Token, which
+	// does not survive the encoding of the token itself.
+	Scope string `json:"scope,omitempty"`
+
+	CreatedAt time.Time `json:"createdAt"`
+	LastSeen  time.Time `json:"lastSeen"`
+}
+
+// ExpiresAt returns when the session expires: after idle of inactivity, after
+// maxAge since login, or when the ID token expires, whichever comes first. A
+// non-positive duration disables the respective limit.
+func (s *Session) ExpiresAt(idle, maxAge time.Duration) time.Time {
+	var expires time.Time
+	if s.IDToken != nil {
+		expires = s.IDToken.Expiry
+	}
+	if maxAge > 0 {
+		expires = earliest(expires, s.CreatedAt.Add(maxAge))
+	}
+	if idle > 0 {
+		expires = earliest(expires, s.LastSeen.Add(idle))
+	}
+	return expires
+}
+
+// OAuth2Token returns Token with the granted scopes restored.
+func (s *Session) OAuth2Token() *oauth2.Token {
+	if s.Token == nil || s.Scope == "" {
+		return s.Token
+	}
+	return s.Token.WithExtra(map[string]interface{}{"scope": s.Scope})
+}
+
+// SetOAuth2Token replaces the token of the session, for example with the
+// merged token of a step-up authorization.
+func (s *Session) SetOAuth2Token(t *oauth2.Token) {
+	s.Token = t
+	s.Scope = jaccount.TokenScopes(t).String()
+}
+
+func earliest(a, b time.Time) time.Time {
+	if a.IsZero() || b.Before(a) {
+		return b
+	}
+	return a
+}
+
+type contextKey struct{}
+
+// NewContext returns a copy of ctx that carries the session, and the ID token
+// and profile of its user for jaccount.IDTokenFromContext and
+// jaccount.ProfileFromContext.
+func NewContext(ctx context.Context, s *Session) context.Context {
+	ctx = context.WithValue(ctx, contextKey{}, s)
+	if s.Profile != nil {
+		ctx = jaccount.ContextWithProfile(ctx, s.Profile)
+	}
+	return jaccount.ContextWithIDToken(ctx, s.IDToken)
+}
+
+// FromContext returns the session stored in ctx, if any.
+func FromContext(ctx context.Context) (*Session, bool) {
+	s, ok := ctx.Value(contextKey{}).(*Session)
+	return s, ok && s != nil
+}
+
+// CurrentUser returns the ID token of the logged-in user, if any.
+func CurrentUser(ctx context.Context) (*jaccount.IDToken, bool) {
+	s, ok := FromContext(ctx)
+	if !ok || s.IDToken == nil {
+		return nil, false
+	}
+	return s.IDToken, true
+}
@@ -0,0 +1,307 @@
+/*
+Copyright 2021 The Go jAccount Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package session
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/dyweb/go-jaccount/jaccount"
+	"golang.org/x/oauth2"
+	"gopkg.in/square/go-jose.v2"
+	"gopkg.in/square/go-jose.v2/jwt"
+)
+
+func newIDToken(t *testing.T, nonce string, expiry time.Time) string {
+	signer, err := jose.NewSigner(jose.SigningKey{Algorithm: jose.HS256, Key: []byte("secret")}, nil)
+	if err != nil {
+		t.Fatalf("error = %v", err)
+	}
+
+	raw, err := jwt.Signed(signer).Claims(map[string]interface{}{
+		"iss":   jaccount.Issuer,
+		"aud":   "client",
+		"sub":   "user",
+		"exp":   expiry.Unix(),
+		"iat":   time.Now().Unix(),
+		"nonce": nonce,
+		"name":  "test",
+		"code":  "000000000000",
+		"type":  "student",
+	}).CompactSerialize()
+	if err != nil {
+		t.Fatalf("error = %v", err)
+	}
+	return raw
+}
+
+func serve(h http.Handler, target string, cookies []*http.Cookie) *httptest.ResponseRecorder {
+	return serveMethod(h, http.MethodGet, target, cookies)
+}
+
+func serveMethod(h http.Handler, method string, target string, cookies []*http.Cookie) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, target, nil)
+	for _, c := range cookies {
+		req.AddCookie(c)
+	}
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestManager(t *testing.T) {
+	var nonce string
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"access_token": "access",
+			"token_type":   "bearer",
+			"expires_in":   3600,
+			"scope":        "openid basic",
+			"id_token":     newIDToken(t, nonce, time.Now().Add(time.Hour)),
+		})
+	}))
+	defer ts.Close()
+
+	cookieStore, err := NewCookieStore([]byte("0123456789abcdef0123456789abcdef"))
+	if err != nil {
+		t.Fatalf("NewCookieStore() error = %v", err)
+	}
+
+	tests := []struct {
+		name  string
+		store Store
+	}{
+		{"cookie store", cookieStore},
+		{"server store", &ServerStore{Backend: &MemoryBackend{}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := &Manager{
+				Config: &oauth2.Config{
+					ClientID:    "client",
+					Endpoint:    oauth2.Endpoint{AuthURL: ts.URL + "/authorize", TokenURL: ts.URL + "/token"},
+					RedirectURL: "http://localhost/callback",
+					Scopes:      []string{jaccount.ScopeOpenID, jaccount.ScopeBasic},
+				},
+				Store: tt.store,
+			}
+
+			mux := http.NewServeMux()
+			mux.Handle("/login", m.LoginHandler())
+			mux.Handle("/callback", m.CallbackHandler())
+			mux.Handle("/logout", m.LogoutHandler())
+			mux.Handle("/private", m.RequireLogin(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				user, ok := CurrentUser(r.Context())
+				if !ok {
+					t.Errorf("CurrentUser() ok = false")
+					return
+				}
+				s, _ := FromContext(r.Context())
+				if !jaccount.TokenScopes(s.OAuth2Token()).Contains(jaccount.ScopeBasic) {
+					t.Errorf("Session.OAuth2Token() lost the granted scopes")
+				}
+				w.Write([]byte(user.Name))
+			})))
+
+			rec := serve(mux, "/private", nil)
+			if got := rec.Header().Get("Location"); rec.Code != http.StatusFound || got != "/login?return=%2Fprivate" {
+				t.Fatalf("RequireLogin() = %d %q, want redirect to login", rec.Code, got)
+			}
+
+			rec = serve(mux, "/login?return=%2Fprivate", nil)
+			location, err := url.Parse(rec.Header().Get("Location"))
+			if err != nil {
+				t.Fatalf("error = %v", err)
+			}
+			nonce = location.Query().Get("nonce")
+
+			callback := "/callback?" + url.Values{"state": {location.Query().Get("state")}, "code": {"code"}}.Encode()
+			forged := serve(mux, "/callback?state=forged&code=code", rec.Result().Cookies())
+			if forged.Code != http.StatusBadRequest {
+				t.Errorf("CallbackHandler() status = %d for a forged state, want %d", forged.Code, http.StatusBadRequest)
+			}
+
+			rec = serve(mux, callback, rec.Result().Cookies())
+			if got := rec.Header().Get("Location"); rec.Code != http.StatusFound || got != "/private" {
+				t.Fatalf("CallbackHandler() = %d %q, want redirect to /private: %s", rec.Code, got, rec.Body)
+			}
+
+			var session []*http.Cookie
+			for _, c := range rec.Result().Cookies() {
+				if c.Name == DefaultCookieName && c.MaxAge > 0 {
+					session = append(session, c)
+				}
+			}
+
+			rec = serve(mux, "/private", session)
+			if rec.Code != http.StatusOK || rec.Body.String() != "test" {
+				t.Errorf("RequireLogin() = %d %q, want 200 %q", rec.Code, rec.Body, "test")
+			}
+
+			rec = serve(mux, "/logout", session)
+			if rec.Code != http.StatusMethodNotAllowed {
+				t.Errorf("LogoutHandler() status = %d for GET, want %d", rec.Code, http.StatusMethodNotAllowed)
+			}
+			rec = serveMethod(mux, http.MethodPost, "/logout", session)
+			if got := rec.Header().Get("Location"); got != "/" {
+				t.Errorf("LogoutHandler() Location = %q, want %q", got, "/")
+			}
+			if _, ok := tt.store.(*ServerStore); ok {
+				rec = serve(mux, "/private", session)
+				if rec.Code != http.StatusFound {
+					t.Errorf("RequireLogin() status = %d after logout, want %d", rec.Code, http.StatusFound)
+				}
+			}
+		})
+	}
+}
+
+func TestManager_Client(t *testing.T) {
+	refreshes := 0
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/token":
+			refreshes++
+			if got := r.FormValue("refresh_token"); got != "refresh" {
+				t.Errorf("refresh_token = %q, want %q", got, "refresh")
+			}
+			json.NewEncoder(w).Encode(map[string]interface{}{
+				"access_token":  "new",
+				"refresh_token": "rotated",
+				"token_type":    "bearer",
+				"expires_in":    3600,
+			})
+		case "/v1/me/profile":
+			if got := r.Header.Get("Authorization"); got != "Bearer new" {
+				t.Errorf("Authorization = %q, want %q", got, "Bearer new")
+			}
+			w.Write([]byte(`{"errno":0,"error":"success","entities":[{"account":"student"}]}`))
+		}
+	}))
+	defer ts.Close()
+
+	m := &Manager{
+		Config: &oauth2.Config{ClientID: "client", Endpoint: oauth2.Endpoint{TokenURL: ts.URL + "/token"}},
+		Store:  &ServerStore{Backend: &MemoryBackend{}},
+	}
+	now := time.Now()
+	s := &Session{ID: "id", CreatedAt: now, LastSeen: now}
+	s.SetOAuth2Token((&oauth2.Token{AccessToken: "old", RefreshToken: "refresh", Expiry: now.Add(-time.Minute)}).
+		WithExtra(map[string]interface{}{"scope": "openid basic"}))
+	scope := s.Scope
+
+	client := m.Client(context.Background(), s)
+	client.BaseURL, _ = url.Parse(ts.URL)
+	for i := 0; i < 2; i++ {
+		if _, err := client.Profile.Get(context.Background()); err != nil {
+			t.Fatalf("ProfileService.Get() error = %v", err)
+		}
+	}
+	if refreshes != 1 {
+		t.Errorf("token refreshed %d times, want 1", refreshes)
+	}
+	if s.Token.AccessToken != "new" || s.Token.RefreshToken != "rotated" || s.Scope != scope {
+		t.Errorf("Session token = %+v, scope %q", s.Token, s.Scope)
+	}
+
+	// The refreshed token is used by later requests once saved.
+	rec := httptest.NewRecorder()
+	if err := m.Save(rec, httptest.NewRequest(http.MethodGet, "/", nil), s); err != nil {
+		t.Fatalf("Manager.Save() error = %v", err)
+	}
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	for _, c := range rec.Result().Cookies() {
+		req.AddCookie(c)
+	}
+	loaded, err := m.Store.Load(req)
+	if err != nil || loaded == nil {
+		t.Fatalf("Store.Load() = %v, %v", loaded, err)
+	}
+	client = m.Client(context.Background(), loaded)
+	client.BaseURL, _ = url.Parse(ts.URL)
+	if _, err := client.Profile.Get(context.Background()); err != nil {
+		t.Fatalf("ProfileService.Get() error = %v", err)
+	}
+	if refreshes != 1 {
+		t.Errorf("token refreshed %d times after saving, want 1", refreshes)
+	}
+}
+
+func TestCookieStore_Save(t *testing.T) {
+	store, err := NewCookieStore([]byte("0123456789abcdef0123456789abcdef"))
+	if err != nil {
+		t.Fatalf("NewCookieStore() error = %v", err)
+	}
+
+	s := &Session{IDToken: &jaccount.IDToken{Subject: "student"}, Profile: &jaccount.Profile{Account: "student"}}
+	rec := httptest.NewRecorder()
+	if err := store.Save(rec, httptest.NewRequest(http.MethodGet, "/", nil), s, time.Now().Add(time.Hour)); err != nil {
+		t.Fatalf("CookieStore.Save() error = %v", err)
+	}
+	if len(rec.Result().Cookies()) != 1 {
+		t.Errorf("CookieStore.Save() set %d cookies, want 1", len(rec.Result().Cookies()))
+	}
+
+	// Browsers would silently drop the cookie, and the user would be sent to
+	// log in again and again.
+	s.Profile.Name = strings.Repeat("张", 1024)
+	rec = httptest.NewRecorder()
+	if err := store.Save(rec, httptest.NewRequest(http.MethodGet, "/", nil), s, time.Now().Add(time.Hour)); !errors.Is(err, ErrCookieTooLarge) {
+		t.Errorf("CookieStore.Save() error = %v, want %v", err, ErrCookieTooLarge)
+	}
+	if len(rec.Result().Cookies()) != 0 {
+		t.Error("CookieStore.Save() set a cookie that is too large")
+	}
+}
+
+func TestSession_ExpiresAt(t *testing.T) {
+	now := time.Now()
+	s := &Session{
+		IDToken:   &jaccount.IDToken{Expiry: now.Add(2 * time.Hour)},
+		CreatedAt: now.Add(-23 * time.Hour),
+		LastSeen:  now.Add(-10 * time.Minute),
+	}
+
+	tests := []struct {
+		name   string
+		idle   time.Duration
+		maxAge time.Duration
+		want   time.Time
+	}{
+		{"idle", 30 * time.Minute, 24 * time.Hour, now.Add(20 * time.Minute)},
+		{"max age", 4 * time.Hour, 24 * time.Hour, now.Add(time.Hour)},
+		{"ID token", -1, -1, now.Add(2 * time.Hour)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := s.ExpiresAt(tt.idle, tt.maxAge); !got.Equal(tt.want) {
+				t.Errorf("Session.ExpiresAt() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
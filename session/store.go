@@ -0,0 +1,371 @@
+/*
+Copyright 2021 The Go jAccount Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package session
+
+import (
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+// DefaultCookieName is the default name of the session cookie.
+const DefaultCookieName = "jaccount_session"
+
+// ErrNotFound is returned by a Backend when a session does not exist.
+var ErrNotFound = errors.New("session not found")
+
+// Store persists sessions between requests.
+type Store interface {
+	// Load returns the session of the request, or nil if there is none.
+	Load(r *http.Request) (*Session, error)
+
+	// Save stores the session until expires.
+	Save(w http.ResponseWriter, r *http.Request, s *Session, expires time.Time) error
+
+	// Delete removes the session of the request.
+	Delete(w http.ResponseWriter, r *http.Request) error
+}
+
+// CookieOptions configures the cookie that holds a session or its ID.
+type CookieOptions struct {
+	// Name defaults to "jaccount_session".
+	Name   string
+	Path   string
+	Domain string
+
+	// Secure marks the cookie secure. If false, the cookie is still marked
+	// secure when the request was made over TLS, as reported by IsSecure.
+	Secure bool
+
+	// SameSite defaults to http.SameSiteLaxMode, which is needed for the
+	// cookie to be sent on the redirect back from jAccount.
+	SameSite http.SameSite
+}
+
+// IsSecure reports whether r was made over TLS, either directly or to a TLS
+// terminator in front of the server that sets X-Forwarded-Proto. Trusting the
+// header is safe here: a client that spoofs it only makes its own cookies
+// secure.
+func IsSecure(r *http.Request) bool {
+	return r.TLS != nil || strings.EqualFold(r.Header.Get("X-Forwarded-Proto"), "https")
+}
+
+func (o *CookieOptions) cookie(r *http.Request, value string, expires time.Time) *http.Cookie {
+	c := &http.Cookie{
+		Name:     o.CookieName(),
+		Value:    value,
+		Path:     o.Path,
+		Domain:   o.Domain,
+		Secure:   o.Secure || IsSecure(r),
+		HttpOnly: true,
+		SameSite: o.SameSite,
+	}
+	if c.Path == "" {
+		c.Path = "/"
+	}
+	if c.SameSite == 0 {
+		c.SameSite = http.SameSiteLaxMode
+	}
+
+	if value == "" {
+		c.MaxAge = -1
+	} else if !expires.IsZero() {
+		c.Expires = expires
+		c.MaxAge = int(time.Until(expires).Seconds())
+		if c.MaxAge <= 0 {
+			c.MaxAge = -1
+		}
+	}
+	return c
+}
+
+// CookieName returns the name of the cookie.
+func (o *CookieOptions) CookieName() string {
+	if o.Name == "" {
+		return DefaultCookieName
+	}
+	return o.Name
+}
+
+// CookieStore stores sessions in encrypted cookies, so no state is kept on the
+// server. Sessions are encrypted with AES-GCM. Browsers drop cookies larger
+// than about 4 KB, so sessions holding a profile should use a ServerStore.
+type CookieStore struct {
+	Cookie CookieOptions
+
+	codec *codec
+}
+
+// NewCookieStore returns a CookieStore. Each key must be 16, 24 or 32 bytes
+// long. The first key encrypts new cookies, and all keys are tried in order
+// when decrypting, which allows keys to be rotated.
+func NewCookieStore(keys ...[]byte) (*CookieStore, error) {
+	codec, err := newCodec(keys)
+	if err != nil {
+		return nil, err
+	}
+	return &CookieStore{codec: codec}, nil
+}
+
+var errNoCodec = errors.New("session: CookieStore must be created with NewCookieStore")
+
+// maxCookieSize is the size of the largest cookie browsers are required to
+// store, including its name and attributes.
+const maxCookieSize = 4096
+
+// ErrCookieTooLarge is returned by CookieStore.Save when the encrypted session
+// does not fit in a cookie.
+var ErrCookieTooLarge = errors.New("session: session too large for a cookie")
+
+// Load implements Store. Cookies that cannot be decrypted are ignored.
+func (s *CookieStore) Load(r *http.Request) (*Session, error) {
+	if s.codec == nil {
+		return nil, errNoCodec
+	}
+
+	cookie, err := r.Cookie(s.Cookie.CookieName())
+	if err != nil {
+		return nil, nil
+	}
+
+	session := new(Session)
+	if err := s.codec.decode(cookie.Name, cookie.Value, session); err != nil {
+		return nil, nil
+	}
+	return session, nil
+}
+
+// Save implements Store.
+func (s *CookieStore) Save(w http.ResponseWriter, r *http.Request, session *Session, expires time.Time) error {
+	if s.codec == nil {
+		return errNoCodec
+	}
+
+	value, err := s.codec.encode(s.Cookie.CookieName(), session)
+	if err != nil {
+		return err
+	}
+	cookie := s.Cookie.cookie(r, value, expires)
+	if len(cookie.String()) > maxCookieSize {
+		return ErrCookieTooLarge
+	}
+	http.SetCookie(w, cookie)
+	return nil
+}
+
+// Delete implements Store.
+func (s *CookieStore) Delete(w http.ResponseWriter, r *http.Request) error {
+	http.SetCookie(w, s.Cookie.cookie(r, "", time.Time{}))
+	return nil
+}
+
+// Backend is a server-side storage of sessions, such as a database or a
+// shared cache.
+type Backend interface {
+	// Get returns the session with the given ID, or ErrNotFound.
+	Get(ctx context.Context, id string) (*Session, error)
+
+	// Set stores the session, which may be discarded after expires.
+	Set(ctx context.Context, s *Session, expires time.Time) error
+
+	// Delete removes the session with the given ID.
+	Delete(ctx context.Context, id string) error
+}
+
+// ServerStore stores sessions in a Backend and only keeps their random IDs in
+// cookies.
+type ServerStore struct {
+	Backend Backend
+	Cookie  CookieOptions
+}
+
+// Load implements Store.
+func (s *ServerStore) Load(r *http.Request) (*Session, error) {
+	cookie, err := r.Cookie(s.Cookie.CookieName())
+	if err != nil {
+		return nil, nil
+	}
+
+	session, err := s.Backend.Get(r.Context(), cookie.Value)
+	if errors.Is(err, ErrNotFound) {
+		return nil, nil
+	}
+	return session, err
+}
+
+// Save implements Store. A session without an ID is given a new one.
+func (s *ServerStore) Save(w http.ResponseWriter, r *http.Request, session *Session, expires time.Time) error {
+	if session.ID == "" {
+		session.ID = newID()
+	}
+
+	if err := s.Backend.Set(r.Context(), session, expires); err != nil {
+		return err
+	}
+	http.SetCookie(w, s.Cookie.cookie(r, session.ID, expires))
+	return nil
+}
+
+// Delete implements Store.
+func (s *ServerStore) Delete(w http.ResponseWriter, r *http.Request) error {
+	http.SetCookie(w, s.Cookie.cookie(r, "", time.Time{}))
+
+	cookie, err := r.Cookie(s.Cookie.CookieName())
+	if err != nil {
+		return nil
+	}
+	return s.Backend.Delete(r.Context(), cookie.Value)
+}
+
+type memoryEntry struct {
+	session []byte
+	expires time.Time
+}
+
+// MemoryBackend is a Backend that keeps sessions in memory. It is meant for
+// tests and single-instance deployments.
+type MemoryBackend struct {
+	mu       sync.Mutex
+	sessions map[string]memoryEntry
+}
+
+// Get implements Backend.
+func (b *MemoryBackend) Get(ctx context.Context, id string) (*Session, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	entry, ok := b.sessions[id]
+	if !ok {
+		return nil, ErrNotFound
+	}
+	if !entry.expires.IsZero() && time.Now().After(entry.expires) {
+		delete(b.sessions, id)
+		return nil, ErrNotFound
+	}
+
+	// Sessions are stored encoded so that callers cannot modify them
+	// without saving.
+	session := new(Session)
+	if err := json.Unmarshal(entry.session, session); err != nil {
+		return nil, err
+	}
+	return session, nil
+}
+
+// Set implements Backend.
+func (b *MemoryBackend) Set(ctx context.Context, s *Session, expires time.Time) error {
+	data, err := json.Marshal(s)
+	if err != nil {
+		return err
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.sessions == nil {
+		b.sessions = make(map[string]memoryEntry)
+	}
+	b.sessions[s.ID] = memoryEntry{session: data, expires: expires}
+	return nil
+}
+
+// Delete implements Backend.
+func (b *MemoryBackend) Delete(ctx context.Context, id string) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	delete(b.sessions, id)
+	return nil
+}
+
+// codec encrypts values into cookies with AES-GCM. The cookie name is used as
+// additional data, so a value cannot be moved to another cookie.
+type codec struct {
+	aeads []cipher.AEAD
+}
+
+func newCodec(keys [][]byte) (*codec, error) {
+	if len(keys) == 0 {
+		return nil, errors.New("at least one key is required")
+	}
+
+	c := &codec{}
+	for _, key := range keys {
+		block, err := aes.NewCipher(key)
+		if err != nil {
+			return nil, fmt.Errorf("invalid key: %w", err)
+		}
+		aead, err := cipher.NewGCM(block)
+		if err != nil {
+			return nil, err
+		}
+		c.aeads = append(c.aeads, aead)
+	}
+	return c, nil
+}
+
+func (c *codec) encode(name string, v interface{}) (string, error) {
+	plaintext, err := json.Marshal(v)
+	if err != nil {
+		return "", err
+	}
+
+	aead := c.aeads[0]
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return "", err
+	}
+
+	ciphertext := aead.Seal(nonce, nonce, plaintext, []byte(name))
+	return base64.RawURLEncoding.EncodeToString(ciphertext), nil
+}
+
+func (c *codec) decode(name string, value string, v interface{}) error {
+	ciphertext, err := base64.RawURLEncoding.DecodeString(value)
+	if err != nil {
+		return err
+	}
+
+	for _, aead := range c.aeads {
+		if len(ciphertext) < aead.NonceSize() {
+			continue
+		}
+		nonce, sealed := ciphertext[:aead.NonceSize()], ciphertext[aead.NonceSize():]
+		plaintext, err := aead.Open(nil, nonce, sealed, []byte(name))
+		if err == nil {
+			return json.Unmarshal(plaintext, v)
+		}
+	}
+	return errors.New("failed to decrypt cookie")
+}
+
+func newID() string {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		panic(err)
+	}
+	return base64.RawURLEncoding.EncodeToString(b)
+}